@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"sync"
+
+	"github.com/solvaholic/gh-issue-miner/internal/analyzer"
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+	"github.com/solvaholic/gh-issue-miner/internal/util"
+)
+
+// activityWorkers bounds the concurrent issues whose comments and timeline are
+// fetched, like the comment prefetch of graph.
+const activityWorkers = 5
+
+// fetchActivity returns the comments and timeline events of every issue, in
+// the order of issues. Comments returned inline by the listing backend are
+// reused; the timeline is always fetched, since the listing only carries
+// cross-references. Clients come from clients, which the caller keeps for
+// the whole command. The first error stops the remaining work.
+func fetchActivity(ctx context.Context, clients *hostClients, issues []api.Issue) ([][]analyzer.Activity, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	out := make([][]analyzer.Activity, len(issues))
+	var firstErr error
+	var errMu sync.Mutex
+	fail := func(err error) {
+		errMu.Lock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+		errMu.Unlock()
+	}
+
+	sem := make(chan struct{}, activityWorkers)
+	var wg sync.WaitGroup
+	for i := range issues {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			it := issues[idx]
+			// Repo is HOST/OWNER/REPO off the default host; API paths take owner/repo
+			host, ownerRepo := util.SplitRepo(it.Repo)
+			c, err := clients.get(host)
+			if err != nil {
+				fail(err)
+				return
+			}
+			comments := it.CommentList
+			if comments == nil {
+				if comments, err = api.ListIssueComments(ctx, c, ownerRepo, it.Number); err != nil {
+					fail(err)
+					return
+				}
+			}
+			events, err := api.ListIssueTimeline(ctx, c, ownerRepo, it.Number)
+			if err != nil {
+				fail(err)
+				return
+			}
+
+			var acts []analyzer.Activity
+			for _, cm := range comments {
+				acts = append(acts, analyzer.Activity{Kind: "comment", Actor: cm.Author, Association: cm.AuthorAssociation, IsBot: cm.AuthorIsBot, At: cm.CreatedAt})
+			}
+			for _, ev := range events {
+				// comments are taken from the comments endpoint, which also
+				// covers listings that returned them inline
+				if ev.Type == "commented" {
+					continue
+				}
+				acts = append(acts, analyzer.Activity{Kind: ev.Type, Actor: ev.Actor, IsBot: ev.ActorIsBot, At: ev.CreatedAt})
+			}
+			out[idx] = acts
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	// report a cancellation of the caller's context (Ctrl-C) as such
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+// hostClients hands out one API client per GitHub host, so references into
+// another host (for example from github.com into an Enterprise Server) are
+// fetched there. Hosts are keyed by hostKey, so "", "github.com" and
+// "GitHub.com" share the client of the default host. It is safe for
+// concurrent use; the zero value is ready.
+type hostClients struct {
+	mu      sync.Mutex
+	clients map[string]api.RESTClient
+}
+
+// hostKey normalizes host for comparisons: "" for the default host, the
+// lower-cased name otherwise.
+func hostKey(host string) string {
+	if api.IsDefaultHost(host) {
+		return ""
+	}
+	return strings.ToLower(host)
+}
+
+// set makes c the client of host, such as the one a positional issue URL was
+// fetched with.
+func (h *hostClients) set(host string, c api.RESTClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.clients == nil {
+		h.clients = map[string]api.RESTClient{}
+	}
+	h.clients[hostKey(host)] = c
+}
+
+// get returns the client of host, creating it with api.ClientForHost on
+// first use.
+func (h *hostClients) get(host string) (api.RESTClient, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	k := hostKey(host)
+	if c, ok := h.clients[k]; ok {
+		return c, nil
+	}
+	c, err := api.ClientForHost(host)
+	if err != nil {
+		return nil, err
+	}
+	if h.clients == nil {
+		h.clients = map[string]api.RESTClient{}
+	}
+	h.clients[k] = c
+	return c, nil
+}
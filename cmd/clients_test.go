@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+func TestHostClients_OneClientPerHost(t *testing.T) {
+	oldNew, oldHost, oldSettings := api.NewClient, api.NewHostClient, api.Settings
+	defer func() { api.NewClient, api.NewHostClient, api.Settings = oldNew, oldHost, oldSettings }()
+	api.Settings.Host = "github.com"
+	defaults := 0
+	var hosts []string
+	api.NewClient = func() (api.RESTClient, error) {
+		defaults++
+		return &fakeRESTClient{}, nil
+	}
+	api.NewHostClient = func(host string) (api.RESTClient, error) {
+		hosts = append(hosts, host)
+		return &fakeRESTClient{}, nil
+	}
+
+	var clients hostClients
+	first, err := clients.get("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, host := range []string{"github.com", "GitHub.com"} {
+		if c, _ := clients.get(host); c != first {
+			t.Fatalf("expected %q to share the default client", host)
+		}
+	}
+	ghes, _ := clients.get("GHE.example.com")
+	if c, _ := clients.get("ghe.example.com"); c != ghes || c == first {
+		t.Fatalf("expected one separate client for ghe.example.com")
+	}
+	if defaults != 1 || len(hosts) != 1 || hosts[0] != "ghe.example.com" {
+		t.Fatalf("expected one client per host, got %d default and %v", defaults, hosts)
+	}
+
+	// a client set for a host, like that of a positional URL, is reused
+	positional := &fakeRESTClient{}
+	clients.set("ghe2.example.com", positional)
+	if c, _ := clients.get("GHE2.example.com"); c != positional {
+		t.Fatalf("expected the client set for ghe2.example.com")
+	}
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/solvaholic/gh-issue-miner/internal/analyzer"
+	"github.com/solvaholic/gh-issue-miner/internal/output"
+)
+
+var compareRepos repoSelection
+var compareCreated []string
+var compareLimit int
+var compareIncludePRs bool
+var compareLabel string
+var compareState string
+var compareAssignee string
+var compareAuthor string
+var compareUpdated string
+var compareClosed string
+var compareTopLabels int
+
+var compareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compare pulse metrics across repositories or time windows",
+	Long: `Compute pulse metrics for several selections and show them side by side
+with the difference to the first one.
+
+Compare repositories by selecting more than one (repeated --repo, a glob or
+--org); each repository becomes a column. Compare periods by giving --created
+twice or more; each window becomes a column over the union of the selected
+repositories. All other filters apply to every column.`,
+	Example: `  gh issue-miner compare --repo cli/cli --repo cli/go-gh --label bug
+  gh issue-miner compare --repo cli/cli --created 2025-01-01..2025-01-31 --created 2025-02-01..2025-02-28 --format markdown`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext(cmd)
+
+		switch outputFormat {
+		case "text", "json", "markdown":
+		default:
+			return fmt.Errorf("invalid --format value for compare: %s (allowed: text, json, markdown)", outputFormat)
+		}
+
+		repos, err := compareRepos.resolve(ctx)
+		if err != nil {
+			return err
+		}
+
+		type column struct {
+			name    string
+			repos   []string
+			created string
+		}
+		var columns []column
+		switch {
+		case len(compareCreated) >= 2:
+			for _, c := range compareCreated {
+				columns = append(columns, column{name: c, repos: repos, created: c})
+			}
+		case len(repos) >= 2:
+			created := ""
+			if len(compareCreated) == 1 {
+				created = compareCreated[0]
+			}
+			for _, r := range repos {
+				columns = append(columns, column{name: r, repos: []string{r}, created: created})
+			}
+		default:
+			return fmt.Errorf("compare needs at least two repositories or two --created windows")
+		}
+
+		var names []string
+		var metrics []analyzer.PulseMetrics
+		for _, c := range columns {
+			issues, _, err := FetchIssuesMulti(ctx, c.repos, compareLimit, compareIncludePRs, compareLabel, compareState, compareAssignee, compareAuthor, c.created, compareUpdated, compareClosed, "", "")
+			if err != nil {
+				return err
+			}
+			names = append(names, c.name)
+			metrics = append(metrics, analyzer.ComputePulse(issues))
+		}
+		comparison := analyzer.Compare(names, metrics, compareTopLabels)
+
+		// prepare output writer (stdout or file)
+		var out io.Writer = os.Stdout
+		if outputFile != "" {
+			f, err := os.Create(outputFile)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+
+		if outputTemplate != nil {
+			return output.WriteTemplate(out, outputTemplate, comparison)
+		}
+
+		switch outputFormat {
+		case "json":
+			return output.WriteCompareJSON(out, comparison)
+		case "markdown":
+			header := append([]string{"Metric"}, names...)
+			align := []string{"left"}
+			for range names {
+				align = append(align, "right")
+			}
+			return output.WriteMarkdownTable(out, header, align, comparisonCells(comparison))
+		}
+
+		if len(compareCreated) >= 2 {
+			fmt.Fprintf(out, "Repository:\t%s\n\n", strings.Join(repos, ", "))
+		}
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(w, "Metric\t%s\n", strings.Join(names, "\t"))
+		for _, row := range comparisonCells(comparison) {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	},
+}
+
+// comparisonCells renders each row as the metric name followed by one cell per
+// column; columns after the baseline show the difference, as in "12 (+3)".
+func comparisonCells(c analyzer.Comparison) [][]string {
+	var rows [][]string
+	for _, r := range c.Rows {
+		cells := []string{r.Metric}
+		for i, v := range r.Values {
+			cell := strconv.FormatFloat(v, 'f', r.Precision, 64)
+			if i > 0 {
+				d := strconv.FormatFloat(r.Deltas[i], 'f', r.Precision, 64)
+				if !strings.HasPrefix(d, "-") {
+					d = "+" + d
+				}
+				cell += " (" + d + ")"
+			}
+			cells = append(cells, cell)
+		}
+		rows = append(rows, cells)
+	}
+	return rows
+}
+
+func init() {
+	addRepoFlags(compareCmd, &compareRepos)
+	compareCmd.Flags().StringArrayVar(&compareCreated, "created", nil, "Created timeframe; give it twice or more to compare periods (e.g., 2025-01-01..2025-01-31)")
+	compareCmd.Flags().IntVar(&compareLimit, "limit", 100, "Maximum number of issues to analyze per column")
+	compareCmd.Flags().BoolVar(&compareIncludePRs, "include-prs", false, "Include pull requests in results")
+	compareCmd.Flags().StringVar(&compareLabel, "label", "", "Comma-separated label specs (exact, prefix*, or -excluded). Matches issues containing any of these labels")
+	compareCmd.Flags().StringVar(&compareState, "state", "", "Filter by issue state: open, closed")
+	compareCmd.Flags().StringVar(&compareAssignee, "assignee", "", "Filter by assignee username")
+	compareCmd.Flags().StringVar(&compareAuthor, "author", "", "Filter by issue author username")
+	compareCmd.Flags().StringVar(&compareUpdated, "updated", "", "Filter by updated timeframe (e.g., 7d, 2025-01-01)")
+	compareCmd.Flags().StringVar(&compareClosed, "closed", "", "Filter by closed timeframe (e.g., 30d, 2025-01-01..2025-02-01)")
+	compareCmd.Flags().IntVar(&compareTopLabels, "top-labels", 5, "Compare the labels among the N most used of each column")
+	rootCmd.AddCommand(compareCmd)
+}
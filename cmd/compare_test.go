@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/solvaholic/gh-issue-miner/internal/analyzer"
+)
+
+func TestTestServer_Compare(t *testing.T) {
+	startOrgServer(t)
+
+	out, err := runCLI(t, "compare", "--no-cache", "--org", "octo", "--format", "json")
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	var cmp analyzer.Comparison
+	if err := json.Unmarshal([]byte(out), &cmp); err != nil {
+		t.Fatalf("compare output is not JSON: %v\n%s", err, out)
+	}
+	if len(cmp.Columns) != 2 || cmp.Columns[0].Name != "octo/a" || cmp.Columns[1].Name != "octo/b" {
+		t.Fatalf("unexpected columns: %+v", cmp.Columns)
+	}
+	total := cmp.Rows[2]
+	if total.Metric != "Total" || total.Values[0] != 3 || total.Values[1] != 2 || total.Deltas[1] != -1 {
+		t.Fatalf("unexpected Total row: %+v", total)
+	}
+
+	out, err = runCLI(t, "compare", "--no-cache", "--org", "octo", "--format", "markdown")
+	if err != nil {
+		t.Fatalf("compare markdown: %v", err)
+	}
+	if !strings.HasPrefix(out, "| Metric | octo/a | octo/b |\n| :--- | ---: | ---: |\n") || !strings.Contains(out, "| Total | 3 | 2 (-1) |") {
+		t.Fatalf("unexpected markdown:\n%s", out)
+	}
+
+	// two --created windows over one repository: all of octo/a is older than a day
+	out, err = runCLI(t, "compare", "--no-cache", "--repo", "octo/a", "--created", "30d", "--created", "1d", "--format", "json")
+	if err != nil {
+		t.Fatalf("compare periods: %v", err)
+	}
+	cmp = analyzer.Comparison{}
+	if err := json.Unmarshal([]byte(out), &cmp); err != nil {
+		t.Fatalf("compare output is not JSON: %v\n%s", err, out)
+	}
+	if len(cmp.Columns) != 2 || cmp.Columns[0].Name != "30d" || cmp.Rows[2].Values[0] != 3 || cmp.Rows[2].Values[1] != 0 {
+		t.Fatalf("unexpected period comparison: %+v", cmp)
+	}
+	if _, err := runCLI(t, "compare", "--no-cache", "--repo", "octo/a"); err == nil || !strings.Contains(err.Error(), "at least two") {
+		t.Fatalf("expected an error for a single column, got %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+	"github.com/solvaholic/gh-issue-miner/internal/testserver"
+	"github.com/solvaholic/gh-issue-miner/internal/testutil"
+)
+
+// runCLI executes the root command with args as the binary would, including
+// PersistentPreRunE, and returns what the command wrote to --output. Flag values
+// and the api seams changed by the run are restored when it returns.
+func runCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	origClient, origHostClient, origSettings := api.NewClient, api.NewHostClient, api.Settings
+	defer func() {
+		api.NewClient, api.NewHostClient, api.Settings = origClient, origHostClient, origSettings
+		outputTemplate = nil
+		resetFlags(rootCmd)
+	}()
+
+	rootCmd.SilenceUsage, rootCmd.SilenceErrors = true, true
+	out := filepath.Join(t.TempDir(), "out")
+	rootCmd.SetArgs(append(args, "--output", out))
+	err := rootCmd.ExecuteContext(context.Background())
+	b, _ := os.ReadFile(out)
+	return string(b), err
+}
+
+// resetFlags puts every flag of cmd and its subcommands back to its default.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			// DefValue of a slice looks like [a,b]; none of ours need quoting
+			var def []string
+			if d := strings.Trim(f.DefValue, "[]"); d != "" {
+				def = strings.Split(d, ",")
+			}
+			_ = sv.Replace(def)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.PersistentFlags().VisitAll(reset)
+	cmd.Flags().VisitAll(reset)
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
+
+func TestReplayFixtures_EndToEnd(t *testing.T) {
+	fixtures := filepath.Join("testdata", "fixtures", "demo")
+	testutil.UseFixtures(t, fixtures)
+
+	out, err := runCLI(t, "fetch", "--repo", "octo/demo", "--format", "json")
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	var fetched struct {
+		Repo   string      `json:"repository"`
+		Issues []api.Issue `json:"issues"`
+	}
+	if err := json.Unmarshal([]byte(out), &fetched); err != nil {
+		t.Fatalf("fetch output is not JSON: %v\n%s", err, out)
+	}
+	if len(fetched.Issues) != 3 || fetched.Issues[0].Number != 3 {
+		t.Fatalf("unexpected fetch result: %+v\n%s", fetched.Issues, out)
+	}
+
+	out, err = runCLI(t, "pulse", "--repo", "octo/demo", "--format", "json")
+	if err != nil {
+		t.Fatalf("pulse: %v", err)
+	}
+	if !strings.Contains(out, `"Open": 2`) || !strings.Contains(out, `"Closed": 1`) {
+		t.Fatalf("unexpected pulse output:\n%s", out)
+	}
+
+	out, err = runCLI(t, "graph", "https://github.com/octo/demo/issues/1", "--format", "dot")
+	if err != nil {
+		t.Fatalf("graph: %v", err)
+	}
+	// #1 references #2 in its body; #2's comment references #3 (depth 1 stops there)
+	if !strings.Contains(out, `"octo/demo#1" -> "octo/demo#2" [label="source=timeline, actor=octocat`) {
+		t.Fatalf("missing timeline-annotated edge:\n%s", out)
+	}
+	if !strings.Contains(out, `"octo/demo#2" -> "octo/demo#3"`) {
+		t.Fatalf("missing comment edge:\n%s", out)
+	}
+
+	// --replay serves the same fixtures as testutil.UseFixtures
+	if _, err := runCLI(t, "fetch", "--replay", fixtures, "--repo", "octo/demo", "--limit", "1"); err != nil {
+		t.Fatalf("fetch --replay: %v", err)
+	}
+	if _, err := runCLI(t, "fetch", "--replay", fixtures, "--repo", "octo/unknown"); err == nil || !strings.Contains(err.Error(), "no fixture") {
+		t.Fatalf("expected a missing fixture error, got %v", err)
+	}
+}
+
+// startTestServer serves 150 issues of octo/big: even numbers are by bob, odd by
+// alice, every third has the bug label and every fifth is closed. #1 mentions #2
+// and #2's timeline records the cross-reference.
+func startTestServer(t *testing.T) *testserver.Server {
+	t.Helper()
+	srv := testserver.New()
+	t.Cleanup(srv.Close)
+
+	base := time.Now().UTC().Add(-10 * 24 * time.Hour).Truncate(time.Second)
+	var issues []testserver.Issue
+	for n := 1; n <= 150; n++ {
+		it := testserver.Issue{Number: n, Title: fmt.Sprintf("issue %d", n), Author: "alice", CreatedAt: base.Add(time.Duration(n) * time.Hour)}
+		if n%2 == 0 {
+			it.Author = "bob"
+		}
+		if n%3 == 0 {
+			it.Labels = []string{"bug"}
+		}
+		if n%5 == 0 {
+			closed := it.CreatedAt.Add(time.Hour)
+			it.State, it.ClosedAt, it.UpdatedAt = "closed", &closed, closed
+		}
+		issues = append(issues, it)
+	}
+	issues[0].Body = "depends on #2"
+	issues[1].Timeline = []testserver.Event{{ID: 1, Event: "cross-referenced", Actor: "alice", CreatedAt: base, SourceRepo: "octo/big", SourceNumber: 1}}
+	srv.AddIssues("octo/big", issues...)
+
+	orig := api.Settings.BaseURL
+	api.Settings.BaseURL = srv.URL()
+	t.Cleanup(func() { api.Settings.BaseURL = orig })
+	return srv
+}
+
+func fetchJSON(t *testing.T, args ...string) []api.Issue {
+	t.Helper()
+	out, err := runCLI(t, append([]string{"fetch", "--no-cache", "--repo", "octo/big", "--format", "json"}, args...)...)
+	if err != nil {
+		t.Fatalf("fetch %v: %v", args, err)
+	}
+	var res struct {
+		Issues []api.Issue `json:"issues"`
+	}
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("fetch %v: output is not JSON: %v", args, err)
+	}
+	return res.Issues
+}
+
+func requested(srv *testserver.Server, substr string) bool {
+	for _, r := range srv.Requests() {
+		if strings.Contains(r, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// startOrgServer serves the organization octo with repositories a (issues 1-3),
+// b (issues 1-2) and the archived old (issue 1). octo/a#1 mentions octo/b#2.
+func startOrgServer(t *testing.T) *testserver.Server {
+	t.Helper()
+	srv := testserver.New()
+	t.Cleanup(srv.Close)
+
+	base := time.Now().UTC().Add(-48 * time.Hour).Truncate(time.Second)
+	add := func(repo string, count int, offset time.Duration) {
+		var issues []testserver.Issue
+		for n := 1; n <= count; n++ {
+			issues = append(issues, testserver.Issue{Number: n, Title: fmt.Sprintf("%s %d", repo, n), Author: "alice", CreatedAt: base.Add(offset + time.Duration(n)*time.Hour)})
+		}
+		srv.AddIssues(repo, issues...)
+	}
+	add("octo/a", 3, 0)
+	add("octo/b", 2, 30*time.Minute)
+	add("octo/old", 1, 0)
+	srv.SetArchived("octo/old", true)
+	srv.AddIssues("octo/a", testserver.Issue{Number: 1, Title: "octo/a 1", Author: "alice", Body: "needs octo/b#2", CreatedAt: base.Add(time.Hour)})
+
+	orig := api.Settings.BaseURL
+	api.Settings.BaseURL = srv.URL()
+	t.Cleanup(func() { api.Settings.BaseURL = orig })
+	return srv
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -15,7 +17,7 @@ import (
 	"github.com/solvaholic/gh-issue-miner/internal/util"
 )
 
-var fetchRepo string
+var fetchRepos repoSelection
 var fetchLimit int
 var fetchIncludePRs bool
 var fetchLabel string
@@ -27,24 +29,32 @@ var fetchUpdated string
 var fetchClosed string
 var fetchSort string
 var fetchDirection string
+var fetchColumns []string
 
 var fetchCmd = &cobra.Command{
 	Use:   "fetch",
 	Short: "Fetch list of issues from a repository",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := context.Background()
+		ctx := commandContext(cmd)
 
-		client, err := api.NewClient()
-		if err != nil {
-			return err
+		tabular := outputFormat == "csv" || outputFormat == "tsv"
+		if cmd.Flags().Changed("columns") && !tabular {
+			return fmt.Errorf("--columns requires --format csv or tsv")
+		}
+		for _, c := range fetchColumns {
+			if _, ok := issueColumns[c]; !ok {
+				return fmt.Errorf("unknown --columns value: %s (allowed: %s)", c, strings.Join(issueColumnNames, ", "))
+			}
 		}
 
 		var issues []api.Issue
 		var repoStr string
+		// with several repositories, issue numbers are shown as owner/repo#N
+		multiRepo := false
 
 		// If a positional arg is provided and it's an issue URL, treat it as exclusive with filters.
 		if len(args) > 0 {
-			if r, num, ok := util.ParseIssueURL(args[0]); ok {
+			if host, r, num, ok := util.ParseIssueURL(args[0]); ok {
 				var conflict []string
 				if cmd.Flags().Changed("label") {
 					conflict = append(conflict, "--label")
@@ -55,9 +65,7 @@ var fetchCmd = &cobra.Command{
 				if cmd.Flags().Changed("include-prs") {
 					conflict = append(conflict, "--include-prs")
 				}
-				if cmd.Flags().Changed("repo") {
-					conflict = append(conflict, "--repo")
-				}
+				conflict = append(conflict, changedRepoFlags(cmd)...)
 				if cmd.Flags().Changed("limit") {
 					conflict = append(conflict, "--limit")
 				}
@@ -74,19 +82,27 @@ var fetchCmd = &cobra.Command{
 					return fmt.Errorf("positional issue URL cannot be combined with filters: %s", strings.Join(conflict, ", "))
 				}
 
+				client, err := api.ClientForHost(host)
+				if err != nil {
+					return err
+				}
 				single, err := api.GetIssue(ctx, client, r, num)
 				if err != nil {
 					return err
 				}
 				issues = []api.Issue{single}
-				repoStr = r
+				repoStr = api.QualifyRepo(host, r)
 			}
 		}
 
 		// Otherwise, list issues from detected repo
 		if issues == nil {
-			var err error
-			issues, repoStr, err = FetchIssues(ctx, client, fetchRepo, fetchLimit, fetchIncludePRs, fetchLabel, fetchState, fetchAssignee, fetchAuthor, fetchCreated, fetchUpdated, fetchClosed, fetchSort, fetchDirection)
+			repos, err := fetchRepos.resolve(ctx)
+			if err != nil {
+				return err
+			}
+			multiRepo = len(repos) > 1
+			issues, repoStr, err = FetchIssuesMulti(ctx, repos, fetchLimit, fetchIncludePRs, fetchLabel, fetchState, fetchAssignee, fetchAuthor, fetchCreated, fetchUpdated, fetchClosed, fetchSort, fetchDirection)
 			if err != nil {
 				return err
 			}
@@ -105,13 +121,34 @@ var fetchCmd = &cobra.Command{
 			defer outFile.Close()
 		}
 
+		if outputTemplate != nil {
+			return output.WriteTemplate(out, outputTemplate, output.FetchDocument(repoStr, issues))
+		}
+
 		// JSON output for fetch
 		if outputFormat == "json" {
 			return output.WriteFetchJSON(out, repoStr, issues)
 		}
+		if tabular {
+			var rows [][]string
+			for _, it := range issues {
+				row := make([]string, len(fetchColumns))
+				for i, c := range fetchColumns {
+					row[i] = issueColumns[c](it)
+				}
+				rows = append(rows, row)
+			}
+			return output.WriteTable(out, outputFormat, fetchColumns, rows)
+		}
+
+		if outputFormat == "markdown" {
+			return writeFetchMarkdown(out, issues, multiRepo)
+		}
 
 		// Print repo header when available
-		if repoStr != "" {
+		if multiRepo {
+			fmt.Fprintf(out, "Repositories:\t%s\n\n", repoStr)
+		} else if repoStr != "" {
 			fmt.Fprintf(out, "Repository:\t%s\n\n", repoStr)
 		}
 
@@ -120,15 +157,16 @@ var fetchCmd = &cobra.Command{
 		fmt.Fprintln(w, "#\tstate\ttitle\tlabels\tassignee\tcreated\tupdated\tcomments")
 		for _, it := range issues {
 			labels := strings.Join(it.Labels, ",")
-			assignee := "unassigned"
-			if it.Assignee != "" {
-				assignee = it.Assignee
-			}
+			assignee := issueAssignees(it)
 			title := it.Title
 			// Quote title to keep spaces visible
 			title = fmt.Sprintf("\"%s\"", title)
-			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n",
-				it.Number,
+			number := strconv.Itoa(it.Number)
+			if multiRepo {
+				number = fmt.Sprintf("%s#%d", it.Repo, it.Number)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n",
+				number,
 				it.State,
 				title,
 				labels,
@@ -145,10 +183,10 @@ var fetchCmd = &cobra.Command{
 }
 
 func init() {
-	fetchCmd.Flags().StringVar(&fetchRepo, "repo", "", "Repository in owner/repo format (default: current repo)")
+	addRepoFlags(fetchCmd, &fetchRepos)
 	fetchCmd.Flags().IntVar(&fetchLimit, "limit", 100, "Maximum number of issues to fetch")
 	fetchCmd.Flags().BoolVar(&fetchIncludePRs, "include-prs", false, "Include pull requests in results")
-	fetchCmd.Flags().StringVar(&fetchLabel, "label", "", "Comma-separated label specs (exact or prefix*). Matches issues containing any of these labels")
+	fetchCmd.Flags().StringVar(&fetchLabel, "label", "", "Comma-separated label specs (exact, prefix*, or -excluded). Matches issues containing any of these labels")
 	fetchCmd.Flags().StringVar(&fetchState, "state", "", "Filter by issue state: open, closed")
 	fetchCmd.Flags().StringVar(&fetchAssignee, "assignee", "", "Filter by assignee username")
 	fetchCmd.Flags().StringVar(&fetchAuthor, "author", "", "Filter by issue author username")
@@ -159,9 +197,104 @@ func init() {
 	fetchCmd.Flags().StringVar(&fetchDirection, "direction", "", "Sort direction: asc or desc")
 	// alias --order to --direction for discoverability (bind to same variable)
 	fetchCmd.Flags().StringVar(&fetchDirection, "order", "", "Alias for --direction")
+	fetchCmd.Flags().StringSliceVar(&fetchColumns, "columns", defaultIssueColumns, "Columns for --format csv or tsv: "+strings.Join(issueColumnNames, ", "))
+}
+
+// issueAssignees joins the assignees of it, or says "unassigned".
+func issueAssignees(it api.Issue) string {
+	if len(it.Assignees) > 0 {
+		return strings.Join(it.Assignees, ",")
+	}
+	if it.Assignee != "" {
+		return it.Assignee
+	}
+	return "unassigned"
+}
+
+// writeFetchMarkdown writes the issues as a Markdown table with each number
+// linked to the issue.
+func writeFetchMarkdown(w io.Writer, issues []api.Issue, multiRepo bool) error {
+	var rows [][]string
+	for _, it := range issues {
+		labels := make([]string, len(it.Labels))
+		for i, l := range it.Labels {
+			labels[i] = output.EscapeMarkdown(l)
+		}
+		rows = append(rows, []string{
+			issueLink(it, multiRepo),
+			it.State,
+			output.EscapeMarkdown(it.Title),
+			strings.Join(labels, ", "),
+			output.EscapeMarkdown(issueAssignees(it)),
+			it.CreatedAt.Format("2006-01-02"),
+			it.UpdatedAt.Format("2006-01-02"),
+			strconv.Itoa(it.Comments),
+		})
+	}
+	header := []string{"Issue", "State", "Title", "Labels", "Assignees", "Created", "Updated", "Comments"}
+	align := []string{"left", "left", "left", "left", "left", "left", "left", "right"}
+	return output.WriteMarkdownTable(w, header, align, rows)
 }
 
-// FetchIssues performs the core fetch logic and is exported for testing.
+// issueColumnNames lists the --columns of fetch in documentation order.
+var issueColumnNames = []string{"repo", "number", "state", "state_reason", "title", "body", "labels", "assignees", "author", "author_association", "milestone", "created", "updated", "closed", "comments", "reactions", "locked", "is_pr", "url"}
+
+var defaultIssueColumns = []string{"repo", "number", "state", "title", "labels", "assignees", "author", "created", "updated", "closed", "comments", "url"}
+
+// issueColumns renders one --columns value of an issue. Lists are joined with
+// commas and times use RFC 3339; a missing close time is empty.
+var issueColumns = map[string]func(api.Issue) string{
+	"repo":         func(it api.Issue) string { return it.Repo },
+	"number":       func(it api.Issue) string { return strconv.Itoa(it.Number) },
+	"state":        func(it api.Issue) string { return it.State },
+	"state_reason": func(it api.Issue) string { return it.StateReason },
+	"title":        func(it api.Issue) string { return it.Title },
+	"body":         func(it api.Issue) string { return it.Body },
+	"labels":       func(it api.Issue) string { return strings.Join(it.Labels, ",") },
+	"assignees": func(it api.Issue) string {
+		if len(it.Assignees) == 0 {
+			return it.Assignee
+		}
+		return strings.Join(it.Assignees, ",")
+	},
+	"author":             func(it api.Issue) string { return it.Author },
+	"author_association": func(it api.Issue) string { return it.AuthorAssociation },
+	"milestone":          func(it api.Issue) string { return it.Milestone },
+	"created":            func(it api.Issue) string { return it.CreatedAt.UTC().Format(time.RFC3339) },
+	"updated":            func(it api.Issue) string { return it.UpdatedAt.UTC().Format(time.RFC3339) },
+	"closed": func(it api.Issue) string {
+		if it.ClosedAt == nil {
+			return ""
+		}
+		return it.ClosedAt.UTC().Format(time.RFC3339)
+	},
+	"comments":  func(it api.Issue) string { return strconv.Itoa(it.Comments) },
+	"reactions": func(it api.Issue) string { return strconv.Itoa(it.Reactions.Total) },
+	"locked":    func(it api.Issue) string { return strconv.FormatBool(it.Locked) },
+	"is_pr":     func(it api.Issue) string { return strconv.FormatBool(it.IsPR) },
+	"url":       func(it api.Issue) string { return it.HTMLURL },
+}
+
+// repoClient detects the repository named by repoArg (see util.DetectRepo) and
+// returns a client for its host together with the repository in the form
+// FetchIssues expects: owner/repo, or HOST/OWNER/REPO off the default host.
+func repoClient(repoArg string) (api.RESTClient, string, error) {
+	host, repo, err := util.DetectRepo(repoArg)
+	if err != nil {
+		return nil, "", err
+	}
+	client, err := api.ClientForHost(host)
+	if err != nil {
+		return nil, "", err
+	}
+	return client, api.QualifyRepo(host, repo), nil
+}
+
+// FetchIssues performs the core fetch logic for one repository and is exported
+// for testing. FetchIssuesMulti runs it over several repositories.
+// client must talk to the host of the repository; repoClient returns both.
+// The returned repository is qualified with its host when that is not the
+// default host.
 func FetchIssues(ctx context.Context, client api.RESTClient, repoArg string, limit int, includePRs bool, label string, state string, assignee string, author string, created string, updated string, closed string, sort string, direction string) ([]api.Issue, string, error) {
 	// validate sort/direction
 	if sort != "" {
@@ -178,10 +311,32 @@ func FetchIssues(ctx context.Context, client api.RESTClient, repoArg string, lim
 		}
 	}
 
-	repo, err := util.DetectRepo(repoArg)
+	host, repo, err := util.DetectRepo(repoArg)
 	if err != nil {
 		return nil, "", err
 	}
+	qualified := api.QualifyRepo(host, repo)
+
+	switch selectionBackend {
+	case "", "list":
+	case "search":
+		// the search API applies every filter exactly, so no candidate over-fetch or client-side pass is needed
+		query, err := buildSearchQuery(ctx, client, repo, includePRs, label, state, assignee, author, created, updated, closed)
+		if err != nil {
+			return nil, "", err
+		}
+		if limit > api.SearchResultCap {
+			warnf("the search API returns at most %d results; --limit %d is reduced to %d for %s", api.SearchResultCap, limit, api.SearchResultCap, qualified)
+		}
+		issues, err := api.SearchIssuesFunc(ctx, client, query, limit, sort, strings.ToLower(direction))
+		if err != nil {
+			return nil, "", err
+		}
+		setRepo(issues, qualified)
+		return issues, qualified, nil
+	default:
+		return nil, "", fmt.Errorf("invalid --backend value: %s (allowed: list, search)", selectionBackend)
+	}
 
 	// Expand label specs into exact labels for server-side querying
 	labelsForAPI, fallbackRaw, err := ExpandLabelSpecs(ctx, client, repo, label)
@@ -222,5 +377,13 @@ func FetchIssues(ctx context.Context, client api.RESTClient, repoArg string, lim
 		issues = issues[:limit]
 	}
 
-	return issues, repo, nil
+	setRepo(issues, qualified)
+	return issues, qualified, nil
+}
+
+// setRepo records repo on every issue.
+func setRepo(issues []api.Issue, repo string) {
+	for i := range issues {
+		issues[i].Repo = repo
+	}
 }
@@ -3,7 +3,9 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"strings"
@@ -29,11 +31,7 @@ func (f *fakeClient) Get(path string, out interface{}) error {
 			"labels":     []interface{}{map[string]interface{}{"name": "bug"}},
 			"assignee":   map[string]interface{}{"login": "alice"},
 		}
-		if ptr, ok := out.(*interface{}); ok {
-			*ptr = m
-			return nil
-		}
-		return nil
+		return roundTrip(m, out)
 	}
 
 	// For list endpoints, return an array with one item
@@ -50,16 +48,25 @@ func (f *fakeClient) Get(path string, out interface{}) error {
 				"assignee":   map[string]interface{}{"login": "alice"},
 			},
 		}
-		if ptr, ok := out.(*interface{}); ok {
-			*ptr = items
-			return nil
-		}
-		return nil
+		return roundTrip(items, out)
 	}
 
 	return nil
 }
 
+// roundTrip marshals v and decodes it into out, like a real client decoding a response body.
+func roundTrip(v interface{}, out interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+func (f *fakeClient) GetContext(ctx context.Context, path string, out interface{}) error {
+	return f.Get(path, out)
+}
+
 func captureOutput(f func()) string {
 	old := os.Stdout
 	r, w, _ := os.Pipe()
@@ -116,7 +123,7 @@ func TestFetchIssues_WithMockList(t *testing.T) {
 	}
 
 	// Mock ListIssuesFunc to return all issues regardless of the limit passed
-	api.ListIssuesFunc = func(ctx context.Context, client api.RESTClient, repo string, limit int, state string, labels []string, includePRs bool, sort string, direction string, since *time.Time) ([]api.Issue, error) {
+	api.ListIssuesFunc = func(ctx context.Context, client api.RESTClient, repo string, limit int, state string, labels []string, includePRs bool, assignee string, author string, sort string, direction string, since *time.Time) ([]api.Issue, error) {
 		// Verify that since is nil for this call
 		if since != nil {
 			return nil, errors.New("unexpected since in mock")
@@ -125,7 +132,7 @@ func TestFetchIssues_WithMockList(t *testing.T) {
 	}
 
 	// Call FetchIssues asking for limit=2, expect to get first 2 after client-side filtering (none here)
-	out, repo, err := FetchIssues(context.Background(), (api.RESTClient)(nil), "owner/repo", 2, false, "", "", "", "", "", "", "")
+	out, repo, err := FetchIssues(context.Background(), (api.RESTClient)(nil), "owner/repo", 2, false, "", "", "", "", "", "", "", "", "")
 	if err != nil {
 		t.Fatalf("FetchIssues returned error: %v", err)
 	}
@@ -146,13 +153,13 @@ func TestFetchIssues_UpdatedSincePassed(t *testing.T) {
 
 	// Mock ListIssuesFunc to capture since param
 	var capturedSince *time.Time
-	api.ListIssuesFunc = func(ctx context.Context, client api.RESTClient, repo string, limit int, state string, labels []string, includePRs bool, sort string, direction string, since *time.Time) ([]api.Issue, error) {
+	api.ListIssuesFunc = func(ctx context.Context, client api.RESTClient, repo string, limit int, state string, labels []string, includePRs bool, assignee string, author string, sort string, direction string, since *time.Time) ([]api.Issue, error) {
 		capturedSince = since
 		return []api.Issue{}, nil
 	}
 
 	// Call with updated range that has a start bound
-	_, _, err := FetchIssues(context.Background(), (api.RESTClient)(nil), "owner/repo", 10, false, "", "", "", "60d..45d", "", "", "")
+	_, _, err := FetchIssues(context.Background(), (api.RESTClient)(nil), "owner/repo", 10, false, "", "", "", "", "", "60d..45d", "", "", "")
 	if err != nil {
 		t.Fatalf("FetchIssues returned error: %v", err)
 	}
@@ -160,3 +167,105 @@ func TestFetchIssues_UpdatedSincePassed(t *testing.T) {
 		t.Fatalf("expected since to be passed to ListIssuesFunc")
 	}
 }
+
+func TestTestServer_FetchMarkdown(t *testing.T) {
+	startTestServer(t)
+
+	out, err := runCLI(t, "fetch", "--no-cache", "--repo", "octo/big", "--limit", "1", "--sort", "created", "--direction", "asc", "--format", "markdown")
+	if err != nil {
+		t.Fatalf("fetch markdown: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || lines[0] != "| Issue | State | Title | Labels | Assignees | Created | Updated | Comments |" ||
+		!strings.HasPrefix(lines[2], "| [#1](http://") || !strings.Contains(lines[2], "/octo/big/issues/1) | open | issue 1 |  | unassigned | ") {
+		t.Fatalf("unexpected fetch markdown:\n%s", out)
+	}
+}
+
+func TestTestServer_FetchTabularFormats(t *testing.T) {
+	startTestServer(t)
+
+	out, err := runCLI(t, "fetch", "--no-cache", "--repo", "octo/big", "--limit", "2", "--sort", "created", "--direction", "asc", "--format", "csv", "--columns", "number,author,labels,closed")
+	if err != nil {
+		t.Fatalf("fetch csv: %v", err)
+	}
+	if out != "number,author,labels,closed\n1,alice,,\n2,bob,,\n" {
+		t.Fatalf("unexpected fetch csv:\n%q", out)
+	}
+	out, err = runCLI(t, "fetch", "--no-cache", "--repo", "octo/big", "--limit", "1", "--format", "tsv")
+	if err != nil {
+		t.Fatalf("fetch tsv: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[0], "repo\tnumber\tstate\ttitle\t") || !strings.HasPrefix(lines[1], "octo/big\t150\tclosed\tissue 150\tbug\t") {
+		t.Fatalf("unexpected fetch tsv:\n%s", out)
+	}
+	if _, err := runCLI(t, "fetch", "--no-cache", "--repo", "octo/big", "--columns", "number"); err == nil {
+		t.Fatalf("expected --columns without csv or tsv to fail")
+	}
+	if _, err := runCLI(t, "fetch", "--no-cache", "--repo", "octo/big", "--format", "csv", "--columns", "number,nope"); err == nil {
+		t.Fatalf("expected an unknown column to fail")
+	}
+}
+
+func TestTestServer_FetchTemplate(t *testing.T) {
+	startTestServer(t)
+
+	out, err := runCLI(t, "fetch", "--no-cache", "--repo", "octo/big", "--limit", "2", "--sort", "created", "--direction", "asc",
+		"--template", `{{.repository}}:{{range .issues}} #{{.Number}} {{truncate 6 .Title}} by {{upper .Author}} on {{date "Jan 2" .CreatedAt}};{{end}}`)
+	if err != nil {
+		t.Fatalf("fetch --template: %v", err)
+	}
+	created := time.Now().UTC().Add(-10 * 24 * time.Hour).Truncate(time.Second)
+	want := fmt.Sprintf("octo/big: #1 issue… by ALICE on %s; #2 issue… by BOB on %s;", created.Add(time.Hour).Format("Jan 2"), created.Add(2*time.Hour).Format("Jan 2"))
+	if out != want {
+		t.Fatalf("expected %q, got %q", want, out)
+	}
+
+	if _, err := runCLI(t, "fetch", "--no-cache", "--repo", "octo/big", "--template", "{{.issues", "--limit", "1"); err == nil || !strings.Contains(err.Error(), "parse --template") {
+		t.Fatalf("expected a parse error, got %v", err)
+	}
+	if _, err := runCLI(t, "fetch", "--no-cache", "--repo", "octo/big", "--template", "x", "--format", "json"); err == nil {
+		t.Fatalf("expected --template with --format to fail")
+	}
+}
+
+func TestTestServer_FetchPaginatesAndFilters(t *testing.T) {
+	srv := startTestServer(t)
+
+	issues := fetchJSON(t, "--limit", "120")
+	if len(issues) != 120 || issues[0].Number != 150 || issues[119].Number != 31 {
+		t.Fatalf("expected issues 150..31 newest first, got %d starting at %+v", len(issues), issues[0])
+	}
+	if !requested(srv, "page=2") {
+		t.Fatalf("expected a second page request, got %v", srv.Requests())
+	}
+
+	issues = fetchJSON(t, "--author", "bob", "--label", "bug", "--state", "open")
+	// multiples of 6 that are not multiples of 5: 25 - 5 = 20
+	if len(issues) != 20 {
+		t.Fatalf("expected 20 open bugs by bob, got %d", len(issues))
+	}
+	for _, it := range issues {
+		if it.Number%6 != 0 || it.State != "open" {
+			t.Fatalf("unexpected issue %+v", it)
+		}
+	}
+	if !requested(srv, "creator=bob") || !requested(srv, "labels=bug") {
+		t.Fatalf("expected creator and labels to be sent, got %v", srv.Requests())
+	}
+
+	// range starts are aligned to 00:00 UTC of the day 5 days ago
+	cutoff := time.Now().UTC().Add(-5 * 24 * time.Hour).Truncate(24 * time.Hour)
+	issues = fetchJSON(t, "--updated", "5d..")
+	if len(issues) == 0 {
+		t.Fatalf("expected recently updated issues")
+	}
+	for _, it := range issues {
+		if it.UpdatedAt.Before(cutoff) {
+			t.Fatalf("issue #%d updated %s is older than the since bound", it.Number, it.UpdatedAt)
+		}
+	}
+	if !requested(srv, "since=") {
+		t.Fatalf("expected since to be sent, got %v", srv.Requests())
+	}
+}
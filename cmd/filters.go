@@ -12,14 +12,17 @@ import (
 
 // labelSpec holds compiled label matching rules.
 type labelSpec struct {
-	exact    map[string]bool
-	prefixes []string
+	exact           map[string]bool
+	prefixes        []string
+	excludeExact    map[string]bool
+	excludePrefixes []string
 }
 
 // parseLabelSpecs takes a comma-separated list of label specs like
-// "initiative,epic,batch,foo-*" and returns a labelSpec for matching.
+// "initiative,epic,batch,foo-*,-wontfix" and returns a labelSpec for matching.
+// Specs starting with '-' exclude issues carrying a matching label.
 func parseLabelSpecs(raw string) labelSpec {
-	s := labelSpec{exact: map[string]bool{}}
+	s := labelSpec{exact: map[string]bool{}, excludeExact: map[string]bool{}}
 	if raw == "" {
 		return s
 	}
@@ -28,20 +31,43 @@ func parseLabelSpecs(raw string) labelSpec {
 		if p == "" {
 			continue
 		}
-		if strings.HasSuffix(p, "*") {
+		exclude := strings.HasPrefix(p, "-")
+		if exclude {
+			p = strings.TrimPrefix(p, "-")
+		}
+		switch {
+		case exclude && strings.HasSuffix(p, "*"):
+			s.excludePrefixes = append(s.excludePrefixes, strings.TrimSuffix(p, "*"))
+		case exclude:
+			s.excludeExact[p] = true
+		case strings.HasSuffix(p, "*"):
 			s.prefixes = append(s.prefixes, strings.TrimSuffix(p, "*"))
-		} else {
+		default:
 			s.exact[p] = true
 		}
 	}
 	return s
 }
 
-// matches returns true if any label matches the spec
+// matches returns true if no label is excluded and any label matches the spec.
+// A spec with only exclusions matches every issue without an excluded label.
 func (ls *labelSpec) matches(labels []string) bool {
 	if ls == nil {
 		return false
 	}
+	for _, l := range labels {
+		if ls.excludeExact[l] {
+			return false
+		}
+		for _, p := range ls.excludePrefixes {
+			if strings.HasPrefix(l, p) {
+				return false
+			}
+		}
+	}
+	if len(ls.exact) == 0 && len(ls.prefixes) == 0 {
+		return true
+	}
 	for _, l := range labels {
 		if ls.exact[l] {
 			return true
@@ -220,7 +246,8 @@ func timeInRange(t time.Time, start *time.Time, end *time.Time) bool {
 // ExpandLabelSpecs expands comma-separated label specs where trailing '*' indicates
 // a prefix wildcard. It returns a list of exact labels suitable for server-side
 // querying and a fallbackRaw string (comma-separated) containing any wildcard
-// specs that did not match any repo label (to be applied client-side).
+// specs that did not match any repo label and any '-' exclusion specs
+// (to be applied client-side).
 func ExpandLabelSpecs(ctx context.Context, client api.RESTClient, repo string, raw string) ([]string, string, error) {
 	var exact []string
 	var fallback []string
@@ -232,7 +259,8 @@ func ExpandLabelSpecs(ctx context.Context, client api.RESTClient, repo string, r
 	needLabels := false
 	parts := strings.Split(raw, ",")
 	for _, p := range parts {
-		if strings.HasSuffix(strings.TrimSpace(p), "*") {
+		p = strings.TrimSpace(p)
+		if !strings.HasPrefix(p, "-") && strings.HasSuffix(p, "*") {
 			needLabels = true
 			break
 		}
@@ -253,6 +281,10 @@ func ExpandLabelSpecs(ctx context.Context, client api.RESTClient, repo string, r
 		if p == "" {
 			continue
 		}
+		if strings.HasPrefix(p, "-") {
+			fallback = append(fallback, p)
+			continue
+		}
 		if strings.HasSuffix(p, "*") {
 			prefix := strings.TrimSuffix(p, "*")
 			found := false
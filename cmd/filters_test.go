@@ -74,3 +74,24 @@ func TestParseTimeRange_Invalid(t *testing.T) {
 		t.Fatalf("expected error for invalid input")
 	}
 }
+
+func TestLabelSpecExclusions(t *testing.T) {
+	tests := []struct {
+		raw    string
+		labels []string
+		want   bool
+	}{
+		{"bug,-wontfix", []string{"bug"}, true},
+		{"bug,-wontfix", []string{"bug", "wontfix"}, false},
+		{"-wontfix", []string{"docs"}, true},
+		{"-wontfix", nil, true},
+		{"-area/*", []string{"area/api"}, false},
+		{"bug,docs", []string{"docs"}, true},
+	}
+	for _, tt := range tests {
+		ls := parseLabelSpecs(tt.raw)
+		if got := ls.matches(tt.labels); got != tt.want {
+			t.Fatalf("parseLabelSpecs(%q).matches(%v) = %v, want %v", tt.raw, tt.labels, got, tt.want)
+		}
+	}
+}
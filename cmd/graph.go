@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -17,7 +19,7 @@ import (
 	"github.com/solvaholic/gh-issue-miner/internal/util"
 )
 
-var graphRepo string
+var graphRepos repoSelection
 var graphLimit int
 var graphDepth int
 var graphCrossRepo bool
@@ -32,24 +34,27 @@ var graphUpdated string
 var graphClosed string
 var graphSort string
 var graphDirection string
+var graphJSONVersion int
 
 var graphCmd = &cobra.Command{
 	Use:   "graph",
 	Short: "Build a relationship graph from issues",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := context.Background()
-		client, err := api.NewClient()
-		if err != nil {
-			return err
+		ctx := commandContext(cmd)
+		requestsBefore := api.Requests()
+		if graphJSONVersion != 1 && graphJSONVersion != output.GraphSchemaVersion {
+			return fmt.Errorf("invalid --json-version %d: use 1 or %d", graphJSONVersion, output.GraphSchemaVersion)
 		}
 
+		var client api.RESTClient
 		var issues []api.Issue
-		repo := graphRepo
-		var fallbackPrefixes []string
+		var repo string
+		var repoStr string // the selection, for the report title
+		var err error
 
 		// If given a positional issue URL, fetch that issue and its comments
 		if len(args) > 0 {
-			if r, num, ok := util.ParseIssueURL(args[0]); ok {
+			if host, r, num, ok := util.ParseIssueURL(args[0]); ok {
 				// positional URL is exclusive with selection filters
 				var conflict []string
 				if cmd.Flags().Changed("label") {
@@ -61,9 +66,7 @@ var graphCmd = &cobra.Command{
 				if cmd.Flags().Changed("include-prs") {
 					conflict = append(conflict, "--include-prs")
 				}
-				if cmd.Flags().Changed("repo") {
-					conflict = append(conflict, "--repo")
-				}
+				conflict = append(conflict, changedRepoFlags(cmd)...)
 				if cmd.Flags().Changed("limit") {
 					conflict = append(conflict, "--limit")
 				}
@@ -80,11 +83,16 @@ var graphCmd = &cobra.Command{
 					return fmt.Errorf("positional issue URL cannot be combined with filters: %s", strings.Join(conflict, ", "))
 				}
 
-				repo = r
+				repo = api.QualifyRepo(host, r)
+				repoStr = repo
+				if client, err = api.ClientForHost(host); err != nil {
+					return err
+				}
 				single, err := api.GetIssue(ctx, client, r, num)
 				if err != nil {
 					return err
 				}
+				single.Repo = repo
 				issues = []api.Issue{single}
 				// fetch comments too for references
 				comments, _ := api.ListIssueComments(ctx, client, r, num)
@@ -105,279 +113,455 @@ var graphCmd = &cobra.Command{
 		}
 
 		if issues == nil {
-			if repo == "" {
-				r, err := util.DetectRepo(graphRepo)
-				if err != nil {
-					return err
-				}
-				repo = r
-			}
-			// Prepare labels for server-side filtering: expand trailing-* patterns
-			var labelsForAPI []string
-			var fallbackPrefixes []string
-			if graphLabel != "" {
-				needLabels := false
-				for _, p := range strings.Split(graphLabel, ",") {
-					if strings.HasSuffix(strings.TrimSpace(p), "*") {
-						needLabels = true
-						break
-					}
-				}
-
-				var repoLabels []string
-				if needLabels {
-					repoLabels, _ = api.ListRepoLabels(ctx, client, repo)
-				}
-
-				for _, p := range strings.Split(graphLabel, ",") {
-					p = strings.TrimSpace(p)
-					if p == "" {
-						continue
-					}
-					if strings.HasSuffix(p, "*") {
-						prefix := strings.TrimSuffix(p, "*")
-						found := false
-						for _, rl := range repoLabels {
-							if strings.HasPrefix(rl, prefix) {
-								labelsForAPI = append(labelsForAPI, rl)
-								found = true
-							}
-						}
-						if !found {
-							fallbackPrefixes = append(fallbackPrefixes, p)
-						}
-						continue
-					}
-					labelsForAPI = append(labelsForAPI, p)
-				}
-			}
-
-			// validate sort/direction
-			if graphSort != "" {
-				switch graphSort {
-				case "created", "updated", "comments":
-				default:
-					return fmt.Errorf("invalid --sort value: %s (allowed: created, updated, comments)", graphSort)
-				}
-			}
-			if graphDirection != "" {
-				d := strings.ToLower(graphDirection)
-				if d != "asc" && d != "desc" {
-					return fmt.Errorf("invalid --direction/--order value: %s (allowed: asc, desc)", graphDirection)
-				}
-			}
-
-			// If updated filter has a start bound, push it to the server via `since`
-			uStart, _, uErr := parseTimeRange(graphUpdated)
-			if uErr != nil {
-				return uErr
-			}
-
-			issues, err = api.ListIssuesFunc(ctx, client, repo, graphLimit, graphState, labelsForAPI, graphIncludePRs, graphAssignee, graphAuthor, graphSort, strings.ToLower(graphDirection), uStart)
+			repos, err := graphRepos.resolve(ctx)
 			if err != nil {
 				return err
 			}
-		}
-
-		// apply filters only to the initial issue selection
-		// But if we expanded wildcard patterns above, apply fallback client-side filtering
-		fallbackRaw := strings.Join(fallbackPrefixes, ",")
-		if fallbackRaw != "" {
-			issues, err = filterIssues(issues, graphIncludePRs, graphState, fallbackRaw, graphCreated, graphUpdated, graphClosed)
+			issues, repoStr, err = FetchIssuesMulti(ctx, repos, graphLimit, graphIncludePRs, graphLabel, graphState, graphAssignee, graphAuthor, graphCreated, graphUpdated, graphClosed, graphSort, graphDirection)
 			if err != nil {
 				return err
 			}
-		} else {
-			issues, err = filterIssues(issues, graphIncludePRs, graphState, graphLabel, graphCreated, graphUpdated, graphClosed)
+		}
+
+		opts := graphOptions{Depth: graphDepth, CrossRepo: graphCrossRepo, MaxNodes: graphMaxNodes}
+		g := buildGraph(ctx, client, repo, issues, opts)
+		g.APICalls = api.Requests() - requestsBefore
+		graphOut := g.Edges
+		// the JSON document; version 1 is the bare adjacency
+		var graphDoc interface{} = graphOut
+		if graphJSONVersion != 1 {
+			graphDoc = graphDocument(repoStr, g, opts)
+		}
+
+		// prepare output writer
+		var out io.Writer = os.Stdout
+		var outFile *os.File
+		if outputFile != "" {
+			f, err := os.Create(outputFile)
 			if err != nil {
 				return err
 			}
+			outFile = f
+			out = f
+			defer outFile.Close()
 		}
 
-		// Ensure comments are fetched for every issue (list mode and single-issue mode)
-		// We'll store comments per-issue so we can attribute references to comment authors/timestamps.
-		issueComments := make(map[int][]api.Comment)
-		var icMu sync.Mutex
-		// Fetch comments concurrently with a small worker pool to avoid bursting the API
-		sem := make(chan struct{}, 5)
-		var wg sync.WaitGroup
-		for i := range issues {
-			wg.Add(1)
-			sem <- struct{}{}
-			go func(idx int) {
-				defer wg.Done()
-				defer func() { <-sem }()
-				it := &issues[idx]
-				comments, _ := api.ListIssueComments(ctx, client, repo, it.Number)
-				if len(comments) > 0 {
-					icMu.Lock()
-					issueComments[it.Number] = comments
-					icMu.Unlock()
+		var werr error
+		switch {
+		case outputTemplate != nil:
+			werr = output.WriteTemplate(out, outputTemplate, graphDoc)
+		case outputFormat == "json":
+			werr = output.WriteGraphJSON(out, graphDoc)
+		case outputFormat == "html":
+			werr = output.WriteHTMLReport(out, output.HTMLReport{Title: "Issue reference graph", Repository: repoStr, Generated: time.Now(), Graph: graphModel(g)})
+		case outputFormat == "mermaid":
+			werr = output.WriteGraphMermaid(out, graphModel(g))
+		case outputFormat == "graphml":
+			werr = output.WriteGraphGraphML(out, graphModel(g))
+		case outputFormat == "gexf":
+			werr = output.WriteGraphGEXF(out, graphModel(g))
+		case outputFormat == "dot":
+			werr = output.WriteGraphDOT(out, graphOut)
+		case outputFormat == "csv" || outputFormat == "tsv":
+			// one row per edge, ordered by source node and destination
+			srcs := make([]string, 0, len(graphOut))
+			for src := range graphOut {
+				srcs = append(srcs, src)
+			}
+			sort.Strings(srcs)
+			var rows [][]string
+			for _, src := range srcs {
+				edges := graphOut[src]
+				sort.SliceStable(edges, func(i, j int) bool {
+					if edges[i].Dest != edges[j].Dest {
+						return edges[i].Dest < edges[j].Dest
+					}
+					return edges[i].Timestamp.Before(edges[j].Timestamp)
+				})
+				for _, e := range edges {
+					ts, cid := "", ""
+					if !e.Timestamp.IsZero() {
+						ts = e.Timestamp.UTC().Format(time.RFC3339)
+					}
+					if e.CommentID != 0 {
+						cid = strconv.FormatInt(e.CommentID, 10)
+					}
+					rows = append(rows, []string{src, e.Dest, e.Source, e.Actor, e.Action, ts, cid})
+				}
+			}
+			werr = output.WriteTable(out, outputFormat, []string{"src", "dest", "source", "actor", "action", "timestamp", "comment_id"}, rows)
+		default:
+			// text output: fall back to previous printing style but to chosen writer
+			for _, src := range g.Nodes {
+				fmt.Fprintf(out, "%s\n", src)
+				for _, e := range graphOut[src] {
+					var meta []string
+					meta = append(meta, fmt.Sprintf("source=%s", e.Source))
+					if e.Actor != "" {
+						meta = append(meta, fmt.Sprintf("actor=%s", e.Actor))
+					}
+					if !e.Timestamp.IsZero() {
+						meta = append(meta, fmt.Sprintf("at=%s", e.Timestamp.Format(time.RFC3339)))
+					}
+					if e.Action != "" {
+						meta = append(meta, fmt.Sprintf("action=%s", e.Action))
+					}
+					if e.CommentID != 0 {
+						meta = append(meta, fmt.Sprintf("comment_id=%d", e.CommentID))
+					}
+					fmt.Fprintf(out, "  -> %s  (%s)\n", e.Dest, strings.Join(meta, ", "))
 				}
-			}(i)
+			}
+		}
+		if werr != nil {
+			return werr
 		}
-		wg.Wait()
-
-		// Build adjacency with metadata. We'll use timeline events (if available) to annotate edges
-		type Edge struct {
-			Dest      string
-			Actor     string
-			Timestamp time.Time
-			Action    string
-			Source    string // "timeline", "comment", or "body"
-			CommentID int64
+		if g.Interrupted {
+			return fmt.Errorf("interrupted: wrote partial graph (%d of %d discovered nodes visited)", len(g.Nodes), g.Discovered)
 		}
+		return nil
+	},
+}
+
+func init() {
+	addRepoFlags(graphCmd, &graphRepos)
+	graphCmd.Flags().IntVar(&graphLimit, "limit", 100, "Maximum number of issues to include in the graph")
+	graphCmd.Flags().IntVar(&graphDepth, "depth", 1, "Traversal depth for following references (default: 1)")
+	graphCmd.Flags().BoolVar(&graphCrossRepo, "cross-repo", false, "Allow following references across repositories when recursing")
+	graphCmd.Flags().IntVar(&graphMaxNodes, "max-nodes", 500, "Maximum number of nodes to visit during traversal (0 = unlimited)")
+	graphCmd.Flags().BoolVar(&graphIncludePRs, "include-prs", false, "Include pull requests in the initial issue selection")
+	graphCmd.Flags().StringVar(&graphLabel, "label", "", "Comma-separated label specs (exact, prefix*, or -excluded). Matches issues containing any of these labels")
+	graphCmd.Flags().StringVar(&graphState, "state", "", "Filter by issue state: open, closed")
+	graphCmd.Flags().StringVar(&graphAssignee, "assignee", "", "Filter by assignee username")
+	graphCmd.Flags().StringVar(&graphAuthor, "author", "", "Filter by issue author username")
+	graphCmd.Flags().StringVar(&graphCreated, "created", "", "Filter by created timeframe (e.g., 7d, 2025-01-01, 2025-01-01..2025-01-31)")
+	graphCmd.Flags().StringVar(&graphUpdated, "updated", "", "Filter by updated timeframe (e.g., 7d, 2025-01-01)")
+	graphCmd.Flags().StringVar(&graphClosed, "closed", "", "Filter by closed timeframe (e.g., 30d, 2025-01-01..2025-02-01)")
+	graphCmd.Flags().StringVar(&graphSort, "sort", "", "Sort field: created, updated, comments")
+	graphCmd.Flags().StringVar(&graphDirection, "direction", "", "Sort direction: asc or desc")
+	// alias --order to --direction for discoverability (bind to same variable)
+	graphCmd.Flags().StringVar(&graphDirection, "order", "", "Alias for --direction")
+	graphCmd.Flags().IntVar(&graphJSONVersion, "json-version", output.GraphSchemaVersion, "JSON and --template document version: 2 (nodes, edges and stats) or 1 (the adjacency map of earlier releases)")
+	rootCmd.AddCommand(graphCmd)
+}
 
-		// adj maps source issue -> map[dedupeKey]Edge to prevent duplicate edges
-		adj := map[string]map[string]Edge{}
+// GraphEdge is one reference from an issue to another, as written by the
+// graph outputs.
+type GraphEdge struct {
+	Dest      string    `json:"dest"`
+	Actor     string    `json:"actor,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+	Action    string    `json:"action,omitempty"`
+	Source    string    `json:"source"`
+	CommentID int64     `json:"comment_id,omitempty"`
+}
 
-		// cache timeline lookups per destination to avoid repeated API calls
-		timelineCache := map[string][]api.TimelineEvent{}
-		var tcMu sync.Mutex
+// graphOptions bounds the traversal of buildGraph.
+type graphOptions struct {
+	Depth     int
+	CrossRepo bool
+	MaxNodes  int // 0 = unlimited
+}
 
-		// Use a semaphore to limit concurrent timeline fetches and an inflight map
-		timelineSem := make(chan struct{}, 5)
-		type tlResult struct {
-			evs []api.TimelineEvent
-			err error
+// issueGraph is the result of buildGraph. Nodes lists the visited nodes
+// (owner/repo#N, host-qualified off the default host) in traversal order,
+// Edges holds the outgoing edges of those that have any, and Issues the
+// issues fetched for them. Depths holds the hops from the nearest seed of
+// every node, visited or only referenced, and FetchErrors why a visited node
+// could not be fetched.
+type issueGraph struct {
+	Nodes       []string
+	Edges       map[string][]GraphEdge
+	Issues      map[string]api.Issue
+	Depths      map[string]int
+	FetchErrors map[string]string
+	Seeds       int
+	Discovered  int
+	Truncated   int   // nodes not visited because of opts.MaxNodes
+	APICalls    int64 // HTTP requests sent by the command, set by the caller
+	Interrupted bool  // the traversal was cancelled; the graph is partial
+}
+
+// buildGraph follows the references in the bodies and comments of issues, up
+// to opts.Depth hops, and annotates each edge with the matching timeline
+// event when there is one. client and repo are those of a positional issue
+// URL and may be empty; other hosts get their own clients.
+func buildGraph(ctx context.Context, client api.RESTClient, repo string, issues []api.Issue, opts graphOptions) *issueGraph {
+	var clients hostClients
+	if client != nil {
+		// reuse the client of the positional issue URL
+		host, _ := util.SplitRepo(repo)
+		clients.set(host, client)
+	}
+	// nodeKey names a node owner/repo#N, prefixed with the host off the default host
+	nodeKey := func(host, ownerRepo string, number int) string {
+		return fmt.Sprintf("%s#%d", api.QualifyRepo(host, ownerRepo), number)
+	}
+
+	// Ensure comments are fetched for every issue (list mode and single-issue mode)
+	// We'll store comments per-issue (by node key, as issues may come from
+	// several repositories) so we can attribute references to comment authors/timestamps.
+	issueComments := make(map[string][]api.Comment)
+	var icMu sync.Mutex
+	// Fetch comments concurrently with a small worker pool to avoid bursting the API
+	sem := make(chan struct{}, 5)
+	var wg sync.WaitGroup
+	for i := range issues {
+		// stop handing out work once the traversal is cancelled (Ctrl-C)
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
 		}
-		inflight := map[string]chan tlResult{}
-		var inflightMu sync.Mutex
-
-		// helper to get timeline events for a dest (owner/repo and number)
-		getTimeline := func(ownerRepo string, number int) ([]api.TimelineEvent, error) {
-			key := fmt.Sprintf("%s#%d", ownerRepo, number)
-			// check cache
-			tcMu.Lock()
-			evs, ok := timelineCache[key]
-			tcMu.Unlock()
-			if ok {
-				return evs, nil
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			it := &issues[idx]
+			// skip issues whose comments were returned inline by the listing backend
+			if it.CommentList != nil {
+				return
 			}
+			// Repo is HOST/OWNER/REPO off the default host; API paths take owner/repo
+			host, ownerRepo := util.SplitRepo(it.Repo)
+			c, err := clients.get(host)
+			if err != nil {
+				return
+			}
+			comments, err := api.ListIssueComments(ctx, c, ownerRepo, it.Number)
+			if err == nil {
+				icMu.Lock()
+				issueComments[nodeKey(host, ownerRepo, it.Number)] = comments
+				icMu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Build adjacency with metadata. We'll use timeline events (if available) to annotate edges
+	type Edge struct {
+		Dest      string
+		Actor     string
+		Timestamp time.Time
+		Action    string
+		Source    string // "timeline", "comment", or "body"
+		CommentID int64
+	}
+
+	// adj maps source issue -> map[dedupeKey]Edge to prevent duplicate edges
+	adj := map[string]map[string]Edge{}
+
+	// cache timeline lookups per destination to avoid repeated API calls
+	timelineCache := map[string][]api.TimelineEvent{}
+	var tcMu sync.Mutex
+
+	// Use a semaphore to limit concurrent timeline fetches and an inflight map
+	timelineSem := make(chan struct{}, 5)
+	type tlResult struct {
+		evs []api.TimelineEvent
+		err error
+	}
+	inflight := map[string]chan tlResult{}
+	var inflightMu sync.Mutex
+
+	// helper to get timeline events for a dest (host, owner/repo and number)
+	getTimeline := func(host, ownerRepo string, number int) ([]api.TimelineEvent, error) {
+		key := nodeKey(host, ownerRepo, number)
+		// check cache
+		tcMu.Lock()
+		evs, ok := timelineCache[key]
+		tcMu.Unlock()
+		if ok {
+			return evs, nil
+		}
 
-			// dedupe inflight requests
-			inflightMu.Lock()
-			ch, ok := inflight[key]
-			if ok {
-				inflightMu.Unlock()
-				res := <-ch
+		// dedupe inflight requests
+		inflightMu.Lock()
+		ch, ok := inflight[key]
+		if ok {
+			inflightMu.Unlock()
+			select {
+			case res := <-ch:
 				return res.evs, res.err
+			case <-ctx.Done():
+				return nil, ctx.Err()
 			}
-			ch = make(chan tlResult, 1)
-			inflight[key] = ch
-			inflightMu.Unlock()
-
-			// perform fetch in a goroutine but block here until it's done
-			go func() {
-				timelineSem <- struct{}{}
-				evs, err := api.GetIssueTimeline(ctx, client, ownerRepo, number)
+		}
+		ch = make(chan tlResult, 1)
+		inflight[key] = ch
+		inflightMu.Unlock()
+
+		// perform fetch in a goroutine but block here until it's done
+		go func() {
+			var evs []api.TimelineEvent
+			var err error
+			select {
+			case timelineSem <- struct{}{}:
+				var c api.RESTClient
+				if c, err = clients.get(host); err == nil {
+					evs, err = api.GetIssueTimeline(ctx, c, ownerRepo, number)
+				}
 				<-timelineSem
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
 
-				if err == nil {
-					tcMu.Lock()
-					timelineCache[key] = evs
-					tcMu.Unlock()
-				}
+			if err == nil {
+				tcMu.Lock()
+				timelineCache[key] = evs
+				tcMu.Unlock()
+			}
 
-				inflightMu.Lock()
-				ch <- tlResult{evs: evs, err: err}
-				delete(inflight, key)
-				inflightMu.Unlock()
-				close(ch)
-			}()
+			inflightMu.Lock()
+			ch <- tlResult{evs: evs, err: err}
+			delete(inflight, key)
+			inflightMu.Unlock()
+			close(ch)
+		}()
 
-			res := <-ch
+		select {
+		case res := <-ch:
 			return res.evs, res.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
-
-		// We'll perform a breadth-first traversal up to graphDepth, starting from the initial issues.
-		type visitItem struct {
-			Repo   string
-			Number int
-			Depth  int
+	}
+
+	// We'll perform a breadth-first traversal up to graphDepth, starting from the initial issues.
+	type visitItem struct {
+		Host   string // empty for the default host
+		Repo   string
+		Number int
+		Depth  int
+	}
+
+	maxDepth := opts.Depth
+	allowCross := opts.CrossRepo
+
+	// cache fetched issues by key owner/repo#num
+	issuesCache := map[string]api.Issue{}
+	// comments by key
+	commentsCache := map[string][]api.Comment{}
+
+	// seeded queue: initial issues
+	var q []visitItem
+	nodesSeen := map[string]bool{}
+	nodesCount := 0
+	// the traversal is breadth-first, so the first depth recorded is the shortest
+	depths := map[string]int{}
+	// nodes left out because of opts.MaxNodes
+	truncated := map[string]bool{}
+	fetchErrors := map[string]string{}
+	limitHit := false
+	for _, it := range issues {
+		host, ownerRepo := util.SplitRepo(it.Repo)
+		key := nodeKey(host, ownerRepo, it.Number)
+		issuesCache[key] = it
+		// reuse comments and timelines fetched during selection
+		if it.CommentList != nil {
+			commentsCache[key] = it.CommentList
+		} else if cms, ok := issueComments[key]; ok {
+			commentsCache[key] = cms
 		}
-
-		maxDepth := graphDepth
-		allowCross := graphCrossRepo
-
-		// cache fetched issues by key owner/repo#num
-		issuesCache := map[string]api.Issue{}
-		// comments by key
-		commentsCache := map[string][]api.Comment{}
-
-		// seeded queue: initial issues
-		var q []visitItem
-		nodesSeen := map[string]bool{}
-		nodesCount := 0
-		limitHit := false
-		for _, it := range issues {
-			key := fmt.Sprintf("%s#%d", repo, it.Number)
-			issuesCache[key] = it
-			if !nodesSeen[key] {
-				if graphMaxNodes > 0 && nodesCount >= graphMaxNodes {
-					limitHit = true
-				} else {
-					nodesSeen[key] = true
-					nodesCount++
-					q = append(q, visitItem{Repo: repo, Number: it.Number, Depth: 0})
-				}
+		if it.TimelineEvents != nil {
+			timelineCache[key] = it.TimelineEvents
+		}
+		depths[key] = 0
+		if !nodesSeen[key] {
+			if opts.MaxNodes > 0 && nodesCount >= opts.MaxNodes {
+				limitHit = true
+				truncated[key] = true
+			} else {
+				nodesSeen[key] = true
+				nodesCount++
+				q = append(q, visitItem{Host: host, Repo: ownerRepo, Number: it.Number, Depth: 0})
 			}
 		}
+	}
+	seeds := len(depths)
+
+	// visited set for cycle detection
+	visited := map[string]bool{}
+
+	var order []string
+	interrupted := false
+	for len(q) > 0 {
+		// on cancellation keep what has been collected and write a partial graph
+		if ctx.Err() != nil {
+			interrupted = true
+			break
+		}
+		cur := q[0]
+		q = q[1:]
+		srcKey := nodeKey(cur.Host, cur.Repo, cur.Number)
+		if visited[srcKey] {
+			continue
+		}
+		visited[srcKey] = true
+		order = append(order, srcKey)
 
-		// visited set for cycle detection
-		visited := map[string]bool{}
+		// ensure a header is present even if this node has no outgoing edges
+		if _, ok := adj[srcKey]; !ok {
+			adj[srcKey] = map[string]Edge{}
+		}
 
-		for len(q) > 0 {
-			cur := q[0]
-			q = q[1:]
-			srcKey := fmt.Sprintf("%s#%d", cur.Repo, cur.Number)
-			if visited[srcKey] {
+		// hosts we cannot build a client for (not logged in, not GitHub) keep
+		// their incoming edges but are not expanded
+		curClient, err := clients.get(cur.Host)
+		if err != nil {
+			fetchErrors[srcKey] = err.Error()
+			continue
+		}
+
+		// ensure issue is fetched
+		it, ok := issuesCache[srcKey]
+		if !ok {
+			fetched, err := api.GetIssue(ctx, curClient, cur.Repo, cur.Number)
+			if err != nil {
+				// skip if we cannot fetch the issue
+				fetchErrors[srcKey] = err.Error()
 				continue
 			}
-			visited[srcKey] = true
+			it = fetched
+			issuesCache[srcKey] = it
+		}
 
-			// ensure a header is present even if this node has no outgoing edges
-			if _, ok := adj[srcKey]; !ok {
-				adj[srcKey] = map[string]Edge{}
+		// fetch comments if not present
+		if _, ok := commentsCache[srcKey]; !ok {
+			cms, _ := api.ListIssueComments(ctx, curClient, cur.Repo, cur.Number)
+			if len(cms) > 0 {
+				commentsCache[srcKey] = cms
 			}
+		}
 
-			// ensure issue is fetched
-			it, ok := issuesCache[srcKey]
-			if !ok {
-				fetched, err := api.GetIssue(ctx, client, cur.Repo, cur.Number)
-				if err != nil {
-					// skip if we cannot fetch the issue
-					continue
-				}
-				it = fetched
-				issuesCache[srcKey] = it
+		// parse refs from body
+		srcRepo := cur.Repo
+		srcQualified := api.QualifyRepo(cur.Host, cur.Repo)
+		bodyRefs := parser.ParseReferences(it.Body)
+		for _, r := range bodyRefs {
+			var destOwner string
+			if r.OwnerRepo != "" {
+				destOwner = r.OwnerRepo
+			} else {
+				destOwner = srcRepo
 			}
-
-			// fetch comments if not present
-			if _, ok := commentsCache[srcKey]; !ok {
-				cms, _ := api.ListIssueComments(ctx, client, cur.Repo, cur.Number)
-				if len(cms) > 0 {
-					commentsCache[srcKey] = cms
-				}
+			// only full URLs name a host; other references stay on the source host
+			destHost := cur.Host
+			if r.Host != "" {
+				destHost = r.Host
 			}
+			destKey := nodeKey(destHost, destOwner, r.Number)
+			// timelines only record references made on the same host
+			sameHost := hostKey(destHost) == hostKey(cur.Host)
 
-			// parse refs from body
-			srcRepo := cur.Repo
-			bodyRefs := parser.ParseReferences(it.Body)
-			for _, r := range bodyRefs {
-				var destOwner string
-				if r.OwnerRepo != "" {
-					destOwner = r.OwnerRepo
-				} else {
-					destOwner = srcRepo
-				}
-				destKey := fmt.Sprintf("%s#%d", destOwner, r.Number)
-
-				var edge Edge
-				edge.Dest = destKey
-				edge.Source = "body"
+			var edge Edge
+			edge.Dest = destKey
+			edge.Source = "body"
 
-				if evs, err := getTimeline(destOwner, r.Number); err == nil {
+			if sameHost {
+				if evs, err := getTimeline(destHost, destOwner, r.Number); err == nil {
 					for _, ev := range evs {
 						if ev.SourceIssueNumber == cur.Number && (ev.SourceOwnerRepo == "" || ev.SourceOwnerRepo == srcRepo || ev.SourceOwnerRepo == destOwner) {
 							edge.Actor = ev.Actor
@@ -388,54 +572,65 @@ var graphCmd = &cobra.Command{
 						}
 					}
 				}
+			}
 
-				dk := fmt.Sprintf("%s|%s|%s|%s|%d|%d", edge.Dest, edge.Source, edge.Actor, edge.Action, edge.Timestamp.UnixNano(), edge.CommentID)
-				if _, ok := adj[srcKey]; !ok {
-					adj[srcKey] = map[string]Edge{}
-				}
-				adj[srcKey][dk] = edge
-
-				// follow this destination if depth allows
-				if cur.Depth+1 <= maxDepth {
-					// decide cross-repo expansion
-					if destOwner == cur.Repo || allowCross {
-						// enqueue dest if we haven't seen it and haven't hit the node limit
-						if !visited[destKey] {
-							if !nodesSeen[destKey] {
-								if graphMaxNodes > 0 && nodesCount >= graphMaxNodes {
-									limitHit = true
-								} else {
-									nodesSeen[destKey] = true
-									nodesCount++
-									q = append(q, visitItem{Repo: destOwner, Number: r.Number, Depth: cur.Depth + 1})
-								}
+			dk := fmt.Sprintf("%s|%s|%s|%s|%d|%d", edge.Dest, edge.Source, edge.Actor, edge.Action, edge.Timestamp.UnixNano(), edge.CommentID)
+			if _, ok := adj[srcKey]; !ok {
+				adj[srcKey] = map[string]Edge{}
+			}
+			adj[srcKey][dk] = edge
+			if _, ok := depths[destKey]; !ok {
+				depths[destKey] = cur.Depth + 1
+			}
+
+			// follow this destination if depth allows
+			if cur.Depth+1 <= maxDepth {
+				// decide cross-repo expansion
+				if api.QualifyRepo(destHost, destOwner) == srcQualified || allowCross {
+					// enqueue dest if we haven't seen it and haven't hit the node limit
+					if !visited[destKey] {
+						if !nodesSeen[destKey] {
+							if opts.MaxNodes > 0 && nodesCount >= opts.MaxNodes {
+								limitHit = true
+								truncated[destKey] = true
+							} else {
+								nodesSeen[destKey] = true
+								nodesCount++
+								q = append(q, visitItem{Host: destHost, Repo: destOwner, Number: r.Number, Depth: cur.Depth + 1})
 							}
 						}
 					}
 				}
 			}
+		}
 
-			// parse refs from comments and attribute
-			if cms, ok := commentsCache[srcKey]; ok {
-				for _, c := range cms {
-					crefs := parser.ParseReferences(c.Body)
-					for _, r := range crefs {
-						var destOwner string
-						if r.OwnerRepo != "" {
-							destOwner = r.OwnerRepo
-						} else {
-							destOwner = srcRepo
-						}
-						destKey := fmt.Sprintf("%s#%d", destOwner, r.Number)
-
-						var edge Edge
-						edge.Dest = destKey
-						edge.Source = "comment"
-						edge.Actor = c.Author
-						edge.Timestamp = c.CreatedAt
-						edge.CommentID = c.ID
-
-						if evs, err := getTimeline(destOwner, r.Number); err == nil {
+		// parse refs from comments and attribute
+		if cms, ok := commentsCache[srcKey]; ok {
+			for _, c := range cms {
+				crefs := parser.ParseReferences(c.Body)
+				for _, r := range crefs {
+					var destOwner string
+					if r.OwnerRepo != "" {
+						destOwner = r.OwnerRepo
+					} else {
+						destOwner = srcRepo
+					}
+					destHost := cur.Host
+					if r.Host != "" {
+						destHost = r.Host
+					}
+					destKey := nodeKey(destHost, destOwner, r.Number)
+					sameHost := hostKey(destHost) == hostKey(cur.Host)
+
+					var edge Edge
+					edge.Dest = destKey
+					edge.Source = "comment"
+					edge.Actor = c.Author
+					edge.Timestamp = c.CreatedAt
+					edge.CommentID = c.ID
+
+					if sameHost {
+						if evs, err := getTimeline(destHost, destOwner, r.Number); err == nil {
 							for _, ev := range evs {
 								if ev.SourceIssueNumber == cur.Number && (ev.SourceOwnerRepo == "" || ev.SourceOwnerRepo == srcRepo || ev.SourceOwnerRepo == destOwner) {
 									edge.Actor = ev.Actor
@@ -447,124 +642,150 @@ var graphCmd = &cobra.Command{
 								}
 							}
 						}
+					}
 
-						dk := fmt.Sprintf("%s|%s|%s|%s|%d|%d", edge.Dest, edge.Source, edge.Actor, edge.Action, edge.Timestamp.UnixNano(), edge.CommentID)
-						if _, ok := adj[srcKey]; !ok {
-							adj[srcKey] = map[string]Edge{}
-						}
-						adj[srcKey][dk] = edge
-
-						if cur.Depth+1 <= maxDepth {
-							if destOwner == cur.Repo || allowCross {
-								if !visited[destKey] {
-									if !nodesSeen[destKey] {
-										if graphMaxNodes > 0 && nodesCount >= graphMaxNodes {
-											limitHit = true
-										} else {
-											nodesSeen[destKey] = true
-											nodesCount++
-											q = append(q, visitItem{Repo: destOwner, Number: r.Number, Depth: cur.Depth + 1})
-										}
+					dk := fmt.Sprintf("%s|%s|%s|%s|%d|%d", edge.Dest, edge.Source, edge.Actor, edge.Action, edge.Timestamp.UnixNano(), edge.CommentID)
+					if _, ok := adj[srcKey]; !ok {
+						adj[srcKey] = map[string]Edge{}
+					}
+					adj[srcKey][dk] = edge
+					if _, ok := depths[destKey]; !ok {
+						depths[destKey] = cur.Depth + 1
+					}
+
+					if cur.Depth+1 <= maxDepth {
+						if api.QualifyRepo(destHost, destOwner) == srcQualified || allowCross {
+							if !visited[destKey] {
+								if !nodesSeen[destKey] {
+									if opts.MaxNodes > 0 && nodesCount >= opts.MaxNodes {
+										limitHit = true
+										truncated[destKey] = true
+									} else {
+										nodesSeen[destKey] = true
+										nodesCount++
+										q = append(q, visitItem{Host: destHost, Repo: destOwner, Number: r.Number, Depth: cur.Depth + 1})
 									}
 								}
 							}
 						}
 					}
 				}
-
-				if limitHit {
-					fmt.Fprintf(os.Stderr, "warning: traversal hit --max-nodes=%d; some referenced nodes were not expanded\n", graphMaxNodes)
-				}
 			}
-		}
 
-		// Build a serializable adjacency map
-		type GraphEdge struct {
-			Dest      string    `json:"dest"`
-			Actor     string    `json:"actor,omitempty"`
-			Timestamp time.Time `json:"timestamp,omitempty"`
-			Action    string    `json:"action,omitempty"`
-			Source    string    `json:"source"`
-			CommentID int64     `json:"comment_id,omitempty"`
+			if limitHit {
+				warnf("traversal hit --max-nodes=%d; some referenced nodes were not expanded", opts.MaxNodes)
+			}
 		}
-
-		graphOut := map[string][]GraphEdge{}
-		for src, edges := range adj {
-			for _, e := range edges {
-				ge := GraphEdge{
-					Dest:      e.Dest,
-					Actor:     e.Actor,
-					Timestamp: e.Timestamp,
-					Action:    e.Action,
-					Source:    e.Source,
-					CommentID: e.CommentID,
-				}
-				graphOut[src] = append(graphOut[src], ge)
+	}
+
+	// flatten the deduplicated edges into the serializable adjacency
+	graphOut := map[string][]GraphEdge{}
+	for src, edges := range adj {
+		for _, e := range edges {
+			ge := GraphEdge{
+				Dest:      e.Dest,
+				Actor:     e.Actor,
+				Timestamp: e.Timestamp,
+				Action:    e.Action,
+				Source:    e.Source,
+				CommentID: e.CommentID,
 			}
+			graphOut[src] = append(graphOut[src], ge)
 		}
+	}
+
+	return &issueGraph{
+		Nodes:       order,
+		Edges:       graphOut,
+		Issues:      issuesCache,
+		Depths:      depths,
+		FetchErrors: fetchErrors,
+		Seeds:       seeds,
+		Discovered:  nodesCount,
+		Truncated:   len(truncated),
+		Interrupted: interrupted || ctx.Err() != nil,
+	}
+}
 
-		// prepare output writer
-		var out io.Writer = os.Stdout
-		var outFile *os.File
-		if outputFile != "" {
-			f, err := os.Create(outputFile)
-			if err != nil {
-				return err
+// graphModel converts the graph for the graph writers of the output package.
+// Nodes come in traversal order, followed by the referenced nodes that were
+// not visited; those link to their issue page on the host.
+func graphModel(g *issueGraph) *output.Graph {
+	m := &output.Graph{Nodes: []output.GraphNode{}, Edges: []output.GraphEdge{}}
+	visited := map[string]bool{}
+	for _, id := range g.Nodes {
+		visited[id] = true
+	}
+	seen := map[string]bool{}
+	addNode := func(id string) {
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		repo, number := splitNodeKey(id)
+		n := output.GraphNode{ID: id, Repo: repo, Number: number, URL: nodeURL(id), Depth: g.Depths[id], Visited: visited[id], FetchError: g.FetchErrors[id]}
+		if it, ok := g.Issues[id]; ok {
+			n.Title, n.State, n.Labels, n.Author, n.IsPR = it.Title, it.State, it.Labels, it.Author, it.IsPR
+			if it.HTMLURL != "" {
+				n.URL = it.HTMLURL
 			}
-			outFile = f
-			out = f
-			defer outFile.Close()
+			if !it.CreatedAt.IsZero() {
+				created := it.CreatedAt
+				n.CreatedAt = &created
+			}
+			n.ClosedAt = it.ClosedAt
 		}
-
-		switch outputFormat {
-		case "json":
-			return output.WriteGraphJSON(out, graphOut)
-		case "dot":
-			return output.WriteGraphDOT(out, graphOut)
-		default:
-			// text output: fall back to previous printing style but to chosen writer
-			for src, edges := range adj {
-				fmt.Fprintf(out, "%s\n", src)
-				for _, e := range edges {
-					var meta []string
-					meta = append(meta, fmt.Sprintf("source=%s", e.Source))
-					if e.Actor != "" {
-						meta = append(meta, fmt.Sprintf("actor=%s", e.Actor))
-					}
-					if !e.Timestamp.IsZero() {
-						meta = append(meta, fmt.Sprintf("at=%s", e.Timestamp.Format(time.RFC3339)))
-					}
-					if e.Action != "" {
-						meta = append(meta, fmt.Sprintf("action=%s", e.Action))
-					}
-					if e.CommentID != 0 {
-						meta = append(meta, fmt.Sprintf("comment_id=%d", e.CommentID))
-					}
-					fmt.Fprintf(out, "  -> %s  (%s)\n", e.Dest, strings.Join(meta, ", "))
-				}
+		m.Nodes = append(m.Nodes, n)
+	}
+	for _, src := range g.Nodes {
+		addNode(src)
+	}
+	for _, src := range g.Nodes {
+		for _, e := range g.Edges[src] {
+			addNode(e.Dest)
+			ge := output.GraphEdge{From: src, To: e.Dest, Source: e.Source, Actor: e.Actor, Action: e.Action, CommentID: e.CommentID}
+			if !e.Timestamp.IsZero() {
+				ts := e.Timestamp
+				ge.Timestamp = &ts
 			}
-			return nil
+			m.Edges = append(m.Edges, ge)
 		}
-	},
+	}
+	return m
 }
 
-func init() {
-	graphCmd.Flags().StringVar(&graphRepo, "repo", "", "Repository in owner/repo format (default: current repo)")
-	graphCmd.Flags().IntVar(&graphLimit, "limit", 100, "Maximum number of issues to include in the graph")
-	graphCmd.Flags().IntVar(&graphDepth, "depth", 1, "Traversal depth for following references (default: 1)")
-	graphCmd.Flags().BoolVar(&graphCrossRepo, "cross-repo", false, "Allow following references across repositories when recursing")
-	graphCmd.Flags().IntVar(&graphMaxNodes, "max-nodes", 500, "Maximum number of nodes to visit during traversal (0 = unlimited)")
-	graphCmd.Flags().BoolVar(&graphIncludePRs, "include-prs", false, "Include pull requests in the initial issue selection")
-	graphCmd.Flags().StringVar(&graphLabel, "label", "", "Comma-separated label specs (exact or prefix*). Matches issues containing any of these labels")
-	graphCmd.Flags().StringVar(&graphState, "state", "", "Filter by issue state: open, closed")
-	graphCmd.Flags().StringVar(&graphAssignee, "assignee", "", "Filter by assignee username")
-	graphCmd.Flags().StringVar(&graphAuthor, "author", "", "Filter by issue author username")
-	graphCmd.Flags().StringVar(&graphCreated, "created", "", "Filter by created timeframe (e.g., 7d, 2025-01-01, 2025-01-01..2025-01-31)")
-	graphCmd.Flags().StringVar(&graphUpdated, "updated", "", "Filter by updated timeframe (e.g., 7d, 2025-01-01)")
-	graphCmd.Flags().StringVar(&graphClosed, "closed", "", "Filter by closed timeframe (e.g., 30d, 2025-01-01..2025-02-01)")
-	graphCmd.Flags().StringVar(&graphSort, "sort", "", "Sort field: created, updated, comments")
-	graphCmd.Flags().StringVar(&graphDirection, "direction", "", "Sort direction: asc or desc")
-	// alias --order to --direction for discoverability (bind to same variable)
-	graphCmd.Flags().StringVar(&graphDirection, "order", "", "Alias for --direction")
-	rootCmd.AddCommand(graphCmd)
+// graphDocument returns the version 2 JSON document of the graph: its nodes
+// and edges, and the statistics of the traversal.
+func graphDocument(repo string, g *issueGraph, opts graphOptions) map[string]interface{} {
+	return output.GraphDocument(repo, graphModel(g), output.GraphStats{
+		Seeds:          g.Seeds,
+		Depth:          opts.Depth,
+		MaxNodes:       opts.MaxNodes,
+		NodesVisited:   len(g.Nodes),
+		NodesTruncated: g.Truncated,
+		LimitHit:       g.Truncated > 0,
+		APICalls:       g.APICalls,
+		Interrupted:    g.Interrupted,
+	})
+}
+
+// splitNodeKey splits a node key into its repository and issue number.
+func splitNodeKey(id string) (string, int) {
+	i := strings.LastIndex(id, "#")
+	if i < 0 {
+		return id, 0
+	}
+	n, _ := strconv.Atoi(id[i+1:])
+	return id[:i], n
+}
+
+// nodeURL returns the web URL of a graph node (owner/repo#N, or
+// HOST/OWNER/REPO#N off the default host).
+func nodeURL(id string) string {
+	repo, number := splitNodeKey(id)
+	host, ownerRepo := util.SplitRepo(repo)
+	if host == "" {
+		host = api.DefaultHost()
+	}
+	return fmt.Sprintf("https://%s/%s/issues/%d", host, ownerRepo, number)
 }
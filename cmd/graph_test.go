@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -46,6 +48,10 @@ func (f *fakeRESTClient) Get(path string, v interface{}) error {
 	return json.Unmarshal(b, v)
 }
 
+func (f *fakeRESTClient) GetContext(ctx context.Context, path string, v interface{}) error {
+	return f.Get(path, v)
+}
+
 func TestGraphTraversalAndCycleAndCrossRepo(t *testing.T) {
 	// prepare fake data
 	now := time.Now().UTC().Format(time.RFC3339)
@@ -151,3 +157,222 @@ func TestGraphTraversalAndCycleAndCrossRepo(t *testing.T) {
 		t.Fatalf("expected owner2/repo#3 to be expanded in output: %s", out2)
 	}
 }
+
+func TestGraphKeepsHostsApart(t *testing.T) {
+	// the same owner/repo#number exists on github.com and on an Enterprise Server
+	dotcom := &fakeRESTClient{responses: map[string]interface{}{
+		"repos/o/r/issues/1":          map[string]interface{}{"number": 1, "body": "see https://ghe.example.com/o/r/issues/1"},
+		"repos/o/r/issues/1/comments": []interface{}{},
+		"repos/o/r/issues/1/timeline": []interface{}{},
+	}}
+	ghes := &fakeRESTClient{responses: map[string]interface{}{
+		"repos/o/r/issues/1":          map[string]interface{}{"number": 1, "body": "blocked by #5"},
+		"repos/o/r/issues/1/comments": []interface{}{},
+		"repos/o/r/issues/1/timeline": []interface{}{},
+		"repos/o/r/issues/5":          map[string]interface{}{"number": 5, "body": ""},
+		"repos/o/r/issues/5/comments": []interface{}{},
+		"repos/o/r/issues/5/timeline": []interface{}{},
+	}}
+	var hosts []string
+	oldNew, oldHost := api.NewClient, api.NewHostClient
+	defer func() { api.NewClient, api.NewHostClient = oldNew, oldHost }()
+	api.NewClient = func() (api.RESTClient, error) { return dotcom, nil }
+	api.NewHostClient = func(host string) (api.RESTClient, error) {
+		hosts = append(hosts, host)
+		return ghes, nil
+	}
+
+	out, err := runCLI(t, "graph", "--hostname", "github.com", "--depth", "2", "--cross-repo", "https://github.com/o/r/issues/1")
+	if err != nil {
+		t.Fatalf("graph: %v", err)
+	}
+	for _, want := range []string{"o/r#1\n  -> ghe.example.com/o/r#1", "ghe.example.com/o/r#1\n  -> ghe.example.com/o/r#5"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in output:\n%s", want, out)
+		}
+	}
+	if len(hosts) != 1 || hosts[0] != "ghe.example.com" {
+		t.Fatalf("expected one client for ghe.example.com, got %v", hosts)
+	}
+}
+
+func TestTestServer_GraphDOT(t *testing.T) {
+	startTestServer(t)
+
+	out, err := runCLI(t, "graph", "--no-cache", "https://github.com/octo/big/issues/1", "--format", "dot")
+	if err != nil {
+		t.Fatalf("graph: %v", err)
+	}
+	if !strings.Contains(out, `"octo/big#1" -> "octo/big#2" [label="source=timeline, actor=alice`) {
+		t.Fatalf("missing timeline edge:\n%s", out)
+	}
+}
+
+func TestTestServer_GraphCSV(t *testing.T) {
+	startTestServer(t)
+
+	out, err := runCLI(t, "graph", "--no-cache", "https://github.com/octo/big/issues/1", "--format", "csv")
+	if err != nil {
+		t.Fatalf("graph csv: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil || len(records) < 2 || strings.Join(records[0], ",") != "src,dest,source,actor,action,timestamp,comment_id" {
+		t.Fatalf("unexpected graph csv (%v):\n%s", err, out)
+	}
+	found := false
+	for _, r := range records[1:] {
+		found = found || (r[0] == "octo/big#1" && r[1] == "octo/big#2" && r[2] == "timeline" && r[3] == "alice")
+	}
+	if !found {
+		t.Fatalf("missing timeline edge:\n%s", out)
+	}
+}
+
+func TestTestServer_GraphTemplate(t *testing.T) {
+	startTestServer(t)
+
+	// graph templates get the JSON document, including version 1
+	out, err := runCLI(t, "graph", "--no-cache", "https://github.com/octo/big/issues/1", "--template", `{{range .edges}}{{.from}}>{{.to}}({{.source}}) {{end}}`)
+	if err != nil {
+		t.Fatalf("graph --template: %v", err)
+	}
+	if !strings.Contains(out, "octo/big#1>octo/big#2(timeline) ") {
+		t.Fatalf("unexpected graph template output: %q", out)
+	}
+	out, err = runCLI(t, "graph", "--no-cache", "https://github.com/octo/big/issues/1", "--json-version", "1", "--template", `{{range $src, $edges := .}}{{range $edges}}{{$src}}>{{.dest}}({{.source}}) {{end}}{{end}}`)
+	if err != nil {
+		t.Fatalf("graph --json-version 1 --template: %v", err)
+	}
+	if !strings.Contains(out, "octo/big#1>octo/big#2(timeline) ") {
+		t.Fatalf("unexpected graph template output: %q", out)
+	}
+}
+
+func TestTestServer_GraphHTML(t *testing.T) {
+	startTestServer(t)
+
+	out, err := runCLI(t, "graph", "--no-cache", "https://github.com/octo/big/issues/1", "--format", "html")
+	if err != nil {
+		t.Fatalf("graph html: %v", err)
+	}
+	if !strings.Contains(out, `"to":"octo/big#2"`) || strings.Contains(out, "<h2>Durations</h2>") {
+		t.Fatalf("unexpected graph html:\n%s", out)
+	}
+}
+
+func TestTestServer_GraphMermaid(t *testing.T) {
+	startTestServer(t)
+
+	out, err := runCLI(t, "graph", "--no-cache", "https://github.com/octo/big/issues/1", "--format", "mermaid")
+	if err != nil {
+		t.Fatalf("graph mermaid: %v", err)
+	}
+	for _, want := range []string{
+		"flowchart LR\n",
+		`  n0["#35;1 issue 1"]`,
+		`  n0 -->|"cross-referenced by alice"| n1`,
+		"  linkStyle 0 stroke:#0969da\n",
+		"  class n0,n1 open\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("mermaid output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestTestServer_GraphGraphMLAndGEXF(t *testing.T) {
+	startTestServer(t)
+
+	out, err := runCLI(t, "graph", "--no-cache", "https://github.com/octo/big/issues/1", "--format", "graphml")
+	if err != nil {
+		t.Fatalf("graph graphml: %v", err)
+	}
+	for _, want := range []string{
+		`<node id="octo/big#1">`,
+		`<data key="title">issue 1</data>`,
+		`<data key="author">alice</data>`,
+		`<data key="depth">0</data>`,
+		`<node id="octo/big#2">`,
+		`<data key="depth">1</data>`,
+		`<edge id="e0" source="octo/big#1" target="octo/big#2">`,
+		`<data key="e_action">cross-referenced</data>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("graphml output lacks %q:\n%s", want, out)
+		}
+	}
+
+	out, err = runCLI(t, "graph", "--no-cache", "https://github.com/octo/big/issues/1", "--format", "gexf")
+	if err != nil {
+		t.Fatalf("graph gexf: %v", err)
+	}
+	for _, want := range []string{
+		`<node id="octo/big#2" label="octo/big#2">`,
+		`<edge id="0" source="octo/big#1" target="octo/big#2" label="cross-referenced">`,
+		`<attvalue for="actor" value="alice"/>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("gexf output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestTestServer_GraphJSON(t *testing.T) {
+	startTestServer(t)
+
+	out, err := runCLI(t, "graph", "--no-cache", "--repo", "octo/big", "--limit", "3", "--max-nodes", "2", "--format", "json")
+	if err != nil {
+		t.Fatalf("graph json: %v", err)
+	}
+	var doc struct {
+		Version int `json:"version"`
+		Nodes   []struct {
+			ID      string   `json:"id"`
+			Title   string   `json:"title"`
+			State   string   `json:"state"`
+			Labels  []string `json:"labels"`
+			IsPR    bool     `json:"is_pr"`
+			Depth   int      `json:"depth"`
+			Visited bool     `json:"visited"`
+		} `json:"nodes"`
+		Edges []struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+		} `json:"edges"`
+		Stats struct {
+			Seeds          int   `json:"seeds"`
+			Depth          int   `json:"depth"`
+			NodesVisited   int   `json:"nodes_visited"`
+			NodesTruncated int   `json:"nodes_truncated"`
+			LimitHit       bool  `json:"limit_hit"`
+			APICalls       int64 `json:"api_calls"`
+		} `json:"stats"`
+	}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out)
+	}
+	if doc.Version != 2 {
+		t.Fatalf("version = %d, want 2", doc.Version)
+	}
+	st := doc.Stats
+	if st.Seeds != 3 || st.Depth != 1 || st.NodesVisited != 2 || st.NodesTruncated != 1 || !st.LimitHit || st.APICalls == 0 {
+		t.Fatalf("unexpected stats: %+v", st)
+	}
+	// the seed left out by --max-nodes is neither visited nor a node
+	visited := 0
+	for _, n := range doc.Nodes {
+		if n.Visited {
+			visited++
+			if n.Title == "" || n.State == "" || n.Depth != 0 {
+				t.Fatalf("visited node lacks issue fields: %+v", n)
+			}
+		}
+	}
+	if visited != 2 {
+		t.Fatalf("visited nodes = %d, want 2: %s", visited, out)
+	}
+
+	if _, err := runCLI(t, "graph", "--no-cache", "--repo", "octo/big", "--json-version", "3", "--format", "json"); err == nil || !strings.Contains(err.Error(), "--json-version") {
+		t.Fatalf("expected an invalid --json-version error, got %v", err)
+	}
+}
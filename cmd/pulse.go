@@ -1,14 +1,15 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
@@ -19,7 +20,7 @@ import (
 	"github.com/solvaholic/gh-issue-miner/internal/util"
 )
 
-var pulseRepo string
+var pulseRepos repoSelection
 var pulseLimit int
 var pulseIncludePRs bool
 var pulseLabel string
@@ -31,22 +32,46 @@ var pulseUpdated string
 var pulseClosed string
 var pulseSort string
 var pulseDirection string
+var pulseInterval string
+var pulsePeriods int
+var pulseDurationsByLabel bool
+var pulseFirstResponse bool
+var pulseExcludeBots bool
+var pulseMaintainersOnly bool
+var pulseGroupBy string
 
 var pulseCmd = &cobra.Command{
 	Use:   "pulse",
 	Short: "Show metrics about repository issues",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		ctx := context.Background()
-		client, err := api.NewClient()
-		if err != nil {
-			return err
-		}
-
+		ctx := commandContext(cmd)
 		var issues []api.Issue
 		var repoStr string
 
+		if cmd.Flags().Changed("periods") && pulseInterval == "" {
+			return fmt.Errorf("--periods requires --interval")
+		}
+		if pulseInterval != "" && pulseInterval != "week" && pulseInterval != "month" {
+			return fmt.Errorf("invalid --interval value: %s (allowed: week, month)", pulseInterval)
+		}
+		if pulseGroupBy != "" && !slices.Contains(analyzer.GroupByFields, pulseGroupBy) {
+			return fmt.Errorf("invalid --group-by value: %s (allowed: %s)", pulseGroupBy, strings.Join(analyzer.GroupByFields, ", "))
+		}
+		if pulseGroupBy != "" && (pulseInterval != "" || pulseFirstResponse || pulseDurationsByLabel) {
+			return fmt.Errorf("--group-by cannot be combined with --interval, --first-response or --durations-by-label")
+		}
+		if (pulseExcludeBots || pulseMaintainersOnly) && !pulseFirstResponse {
+			return fmt.Errorf("--exclude-bots and --maintainers-only require --first-response")
+		}
+		// the csv and tsv trend table has no room for the other metrics
+		if (outputFormat == "csv" || outputFormat == "tsv") && pulseInterval != "" && (pulseFirstResponse || pulseDurationsByLabel) {
+			return fmt.Errorf("--format %s with --interval writes only the trend and cannot include --first-response or --durations-by-label; use --format json for both", outputFormat)
+		}
+
+		// one client per host for the issue URL and the activity fetches
+		var clients hostClients
 		if len(args) > 0 {
-			if r, num, ok := util.ParseIssueURL(args[0]); ok {
+			if host, r, num, ok := util.ParseIssueURL(args[0]); ok {
 				var conflict []string
 				if cmd.Flags().Changed("label") {
 					conflict = append(conflict, "--label")
@@ -66,9 +91,7 @@ var pulseCmd = &cobra.Command{
 				if cmd.Flags().Changed("closed") {
 					conflict = append(conflict, "--closed")
 				}
-				if cmd.Flags().Changed("repo") {
-					conflict = append(conflict, "--repo")
-				}
+				conflict = append(conflict, changedRepoFlags(cmd)...)
 				if cmd.Flags().Changed("limit") {
 					conflict = append(conflict, "--limit")
 				}
@@ -76,63 +99,59 @@ var pulseCmd = &cobra.Command{
 					return fmt.Errorf("positional issue URL cannot be combined with filters: %s", strings.Join(conflict, ", "))
 				}
 
+				client, err := clients.get(host)
+				if err != nil {
+					return err
+				}
 				single, err := api.GetIssue(ctx, client, r, num)
 				if err != nil {
 					return err
 				}
+				repoStr = api.QualifyRepo(host, r)
+				single.Repo = repoStr
 				issues = []api.Issue{single}
-				repoStr = r
 			}
 		}
 
 		if issues == nil {
-			repo, err := util.DetectRepo(pulseRepo)
+			repos, err := pulseRepos.resolve(ctx)
 			if err != nil {
 				return err
 			}
-			repoStr = repo
-
-			// validate sort/direction
-			if pulseSort != "" {
-				switch pulseSort {
-				case "created", "updated", "comments":
-				default:
-					return fmt.Errorf("invalid --sort value: %s (allowed: created, updated, comments)", pulseSort)
-				}
-			}
-			if pulseDirection != "" {
-				d := strings.ToLower(pulseDirection)
-				if d != "asc" && d != "desc" {
-					return fmt.Errorf("invalid --direction/--order value: %s (allowed: asc, desc)", pulseDirection)
-				}
-			}
-
-			// Expand label specs into exact labels for server-side querying
-			labelsForAPI, fallbackRaw, err := ExpandLabelSpecs(ctx, client, repo, pulseLabel)
+			issues, repoStr, err = FetchIssuesMulti(ctx, repos, pulseLimit, pulseIncludePRs, pulseLabel, pulseState, pulseAssignee, pulseAuthor, pulseCreated, pulseUpdated, pulseClosed, pulseSort, pulseDirection)
 			if err != nil {
 				return err
 			}
+		}
 
-			// If updated filter has a start bound, push it to the server via `since`
-			uStart, _, uErr := parseTimeRange(pulseUpdated)
-			if uErr != nil {
-				return uErr
-			}
-
-			issues, err = api.ListIssuesFunc(ctx, client, repo, pulseLimit, pulseState, labelsForAPI, pulseIncludePRs, pulseAssignee, pulseAuthor, pulseSort, strings.ToLower(pulseDirection), uStart)
+		metrics := analyzer.ComputePulse(issues)
+		if pulseInterval != "" {
+			warnTruncatedTrend(len(issues), pulseLimit)
+			trend, err := analyzer.ComputeTrend(issues, pulseInterval, pulsePeriods, time.Now().UTC())
 			if err != nil {
 				return err
 			}
-
-			// apply client-side filters only for any unmatched wildcard prefixes
-			issues, err = filterIssues(issues, pulseIncludePRs, pulseState, fallbackRaw, pulseCreated, pulseUpdated, pulseClosed)
+			metrics.Trend = &trend
+		}
+		if pulseDurationsByLabel {
+			metrics.LabelDurations = analyzer.ComputeLabelDurations(issues, time.Now())
+		}
+		var groups []analyzer.PulseGroup
+		if pulseGroupBy != "" {
+			var err error
+			if groups, err = analyzer.GroupPulse(issues, pulseGroupBy); err != nil {
+				return err
+			}
+		}
+		if pulseFirstResponse {
+			activity, err := fetchActivity(ctx, &clients, issues)
 			if err != nil {
 				return err
 			}
+			rm := analyzer.ComputeFirstResponse(issues, activity, analyzer.ResponseOptions{ExcludeBots: pulseExcludeBots, MaintainersOnly: pulseMaintainersOnly})
+			metrics.FirstResponse = &rm
 		}
 
-		metrics := analyzer.ComputePulse(issues)
-
 		// prepare output writer (stdout or file)
 		var out io.Writer = os.Stdout
 		var outFile *os.File
@@ -146,41 +165,56 @@ var pulseCmd = &cobra.Command{
 			defer outFile.Close()
 		}
 
-		// JSON output for pulse
-		if outputFormat == "json" {
-			return output.WritePulseJSON(out, repoStr, metrics)
+		if outputTemplate != nil {
+			if pulseGroupBy != "" {
+				return output.WriteTemplate(out, outputTemplate, output.PulseGroupsDocument(repoStr, metrics, pulseGroupBy, groups))
+			}
+			return output.WriteTemplate(out, outputTemplate, output.PulseDocument(repoStr, metrics))
 		}
 
-		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
-		fmt.Fprintf(w, "Repository:\t%s\n\n", repoStr)
-
-		// Print filter summary when non-default filters were provided
-		var active []string
-		if cmd.Flags().Changed("label") && pulseLabel != "" {
-			active = append(active, fmt.Sprintf("label=%s", pulseLabel))
-		}
-		if cmd.Flags().Changed("state") && pulseState != "" {
-			active = append(active, fmt.Sprintf("state=%s", pulseState))
+		if outputFormat == "html" {
+			return output.WriteHTMLReport(out, pulseHTMLReport(repoStr, pulseFilters(cmd), metrics, pulseGroupBy, groups))
 		}
-		if cmd.Flags().Changed("include-prs") && pulseIncludePRs {
-			active = append(active, "include-prs=true")
-		}
-		if cmd.Flags().Changed("created") && pulseCreated != "" {
-			active = append(active, fmt.Sprintf("created=%s", pulseCreated))
+
+		// JSON output for pulse
+		if outputFormat == "json" {
+			if pulseGroupBy != "" {
+				return output.WritePulseGroupsJSON(out, repoStr, metrics, pulseGroupBy, groups)
+			}
+			return output.WritePulseJSON(out, repoStr, metrics)
 		}
-		if cmd.Flags().Changed("updated") && pulseUpdated != "" {
-			active = append(active, fmt.Sprintf("updated=%s", pulseUpdated))
+		if outputFormat == "csv" || outputFormat == "tsv" {
+			switch {
+			case metrics.Trend != nil:
+				return output.WriteTable(out, outputFormat, trendCSVHeader, trendCSVRows(metrics.Trend))
+			case pulseGroupBy != "":
+				return output.WriteTable(out, outputFormat, groupCSVHeader, groupCSVRows(groups))
+			}
+			return output.WriteTable(out, outputFormat, []string{"metric", "value"}, pulseCSVRows(metrics))
 		}
-		if cmd.Flags().Changed("closed") && pulseClosed != "" {
-			active = append(active, fmt.Sprintf("closed=%s", pulseClosed))
+
+		if outputFormat == "markdown" {
+			return writePulseMarkdown(out, repoStr, pulseFilters(cmd), metrics, pulseGroupBy, groups)
 		}
-		if cmd.Flags().Changed("limit") && pulseLimit != 100 {
-			active = append(active, fmt.Sprintf("limit=%d", pulseLimit))
+
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		if len(metrics.RepoCounts) > 1 {
+			fmt.Fprintf(w, "Repositories:\t%s\n\n", repoStr)
+		} else {
+			fmt.Fprintf(w, "Repository:\t%s\n\n", repoStr)
 		}
+
+		active := pulseFilters(cmd)
 		if len(active) > 0 {
 			fmt.Fprintf(w, "Filters:\t%s\n\n", strings.Join(active, ", "))
 		}
 
+		// grouped output replaces the single pulse with one row per group
+		if pulseGroupBy != "" {
+			writeGroupsText(w, pulseGroupBy, groups, len(metrics.RepoCounts) > 1)
+			return w.Flush()
+		}
+
 		twW, _, err := term.GetSize(int(os.Stdout.Fd()))
 		if err != nil || twW <= 0 {
 			twW = 80
@@ -207,6 +241,16 @@ var pulseCmd = &cobra.Command{
 				maxVal = v
 			}
 		}
+		for _, v := range metrics.AuthorCounts {
+			if v > maxVal {
+				maxVal = v
+			}
+		}
+		for _, v := range metrics.RepoCounts {
+			if v > maxVal {
+				maxVal = v
+			}
+		}
 		countWidth := 1
 		if maxVal > 0 {
 			countWidth = len(strconv.Itoa(maxVal))
@@ -215,14 +259,51 @@ var pulseCmd = &cobra.Command{
 		fmt.Fprintln(w, "Issues:")
 		fmt.Fprintf(w, "  Open:\t%*d\n  Closed:\t%*d\n  Total:\t%*d\n\n", countWidth, metrics.Open, countWidth, metrics.Closed, countWidth, metrics.Total)
 
+		type kv struct {
+			K string
+			V int
+		}
+
+		// issues per repository when the selection spans several
+		if len(metrics.RepoCounts) > 1 {
+			fmt.Fprintln(w, "By Repository:")
+			var repos []kv
+			for k, v := range metrics.RepoCounts {
+				repos = append(repos, kv{k, v})
+			}
+			sort.Slice(repos, func(i, j int) bool {
+				if repos[i].V != repos[j].V {
+					return repos[i].V > repos[j].V
+				}
+				return repos[i].K < repos[j].K
+			})
+			for _, it := range repos {
+				fmt.Fprintf(w, "  %s\t%*d\n", it.K, countWidth, it.V)
+			}
+			fmt.Fprintln(w)
+		}
+
 		fmt.Fprintln(w, "Activity:")
 		fmt.Fprintf(w, "  Opened (7d/30d/90d):\t%d / %d / %d\n", metrics.Opened7, metrics.Opened30, metrics.Opened90)
 		fmt.Fprintf(w, "  Closed (7d/30d/90d):\t%d / %d / %d\n", metrics.Closed7, metrics.Closed30, metrics.Closed90)
-		fmt.Fprintf(w, "  Avg time to close:\t%.1f days\n\n", metrics.AvgTimeToClose)
+		fmt.Fprintf(w, "  Avg time to close:\t%.1f days\n", metrics.AvgTimeToClose)
+		fmt.Fprintf(w, "  Time to close:\t%s (%d closed)\n", formatDistribution(metrics.TimeToClose, "days"), metrics.TimeToClose.Count)
+		fmt.Fprintf(w, "  Open age:\t%s (%d open)\n", formatDistribution(metrics.OpenAge, "days"), metrics.OpenAge.Count)
+		if fr := metrics.FirstResponse; fr != nil {
+			fmt.Fprintf(w, "  First response:\t%s (%d answered, %d unanswered)\n", formatDistribution(fr.TimeToFirstResponse, "hours"), fr.TimeToFirstResponse.Count, fr.Unanswered)
+		}
+		fmt.Fprintln(w)
+
+		if metrics.Trend != nil {
+			writeTrendText(w, metrics.Trend)
+		}
 
 		fmt.Fprintln(w, "Most Active:")
 		for _, it := range metrics.TopByComments {
 			prefix := fmt.Sprintf("  #%d ", it.Number)
+			if len(metrics.RepoCounts) > 1 {
+				prefix = fmt.Sprintf("  %s#%d ", it.Repo, it.Number)
+			}
 			suffix := fmt.Sprintf(" (%d comments)", it.Comments)
 			avail := twW - len(prefix) - len(suffix) - 1
 			if avail < 10 {
@@ -233,10 +314,6 @@ var pulseCmd = &cobra.Command{
 		}
 		fmt.Fprintln(w)
 
-		type kv struct {
-			K string
-			V int
-		}
 		var labels []kv
 		for k, v := range metrics.LabelCounts {
 			labels = append(labels, kv{k, v})
@@ -251,6 +328,25 @@ var pulseCmd = &cobra.Command{
 		}
 		fmt.Fprintln(w)
 
+		if metrics.LabelDurations != nil {
+			fmt.Fprintln(w, "Durations by Label (days):")
+			fmt.Fprintln(w, "  Label\t\tCount\tMedian\tP75\tP90\tP95\tMax")
+			for i, it := range labels {
+				if i >= 10 {
+					break
+				}
+				d := metrics.LabelDurations[it.K]
+				for _, row := range []struct {
+					name string
+					dist analyzer.Distribution
+				}{{"time to close", d.TimeToClose}, {"open age", d.OpenAge}} {
+					x := row.dist
+					fmt.Fprintf(w, "  %s\t%s\t%d\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\n", it.K, row.name, x.Count, x.Median, x.P75, x.P90, x.P95, x.Max)
+				}
+			}
+			fmt.Fprintln(w)
+		}
+
 		fmt.Fprintln(w, "Assignees:")
 		var ass []kv
 		for k, v := range metrics.AssigneeCounts {
@@ -264,6 +360,23 @@ var pulseCmd = &cobra.Command{
 			fmt.Fprintf(w, "  %s\t%*d\n", it.K, countWidth, it.V)
 		}
 
+		// the author breakdown is only informative when there is more than one author
+		if len(metrics.AuthorCounts) > 1 {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "Authors:")
+			var authors []kv
+			for k, v := range metrics.AuthorCounts {
+				authors = append(authors, kv{k, v})
+			}
+			sort.Slice(authors, func(i, j int) bool { return authors[i].V > authors[j].V })
+			for i, it := range authors {
+				if i >= 10 {
+					break
+				}
+				fmt.Fprintf(w, "  %s\t%*d\n", it.K, countWidth, it.V)
+			}
+		}
+
 		w.Flush()
 
 		return nil
@@ -271,10 +384,10 @@ var pulseCmd = &cobra.Command{
 }
 
 func init() {
-	pulseCmd.Flags().StringVar(&pulseRepo, "repo", "", "Repository in owner/repo format (default: current repo)")
+	addRepoFlags(pulseCmd, &pulseRepos)
 	pulseCmd.Flags().IntVar(&pulseLimit, "limit", 100, "Maximum number of issues to analyze")
 	pulseCmd.Flags().BoolVar(&pulseIncludePRs, "include-prs", false, "Include pull requests in results")
-	pulseCmd.Flags().StringVar(&pulseLabel, "label", "", "Comma-separated label specs (exact or prefix*). Matches issues containing any of these labels")
+	pulseCmd.Flags().StringVar(&pulseLabel, "label", "", "Comma-separated label specs (exact, prefix*, or -excluded). Matches issues containing any of these labels")
 	pulseCmd.Flags().StringVar(&pulseState, "state", "", "Filter by issue state: open, closed")
 	pulseCmd.Flags().StringVar(&pulseAssignee, "assignee", "", "Filter by assignee username")
 	pulseCmd.Flags().StringVar(&pulseAuthor, "author", "", "Filter by issue author username")
@@ -285,9 +398,222 @@ func init() {
 	pulseCmd.Flags().StringVar(&pulseDirection, "direction", "", "Sort direction: asc or desc")
 	// alias --order to --direction for discoverability (bind to same variable)
 	pulseCmd.Flags().StringVar(&pulseDirection, "order", "", "Alias for --direction")
+	pulseCmd.Flags().StringVar(&pulseInterval, "interval", "", "Show a trend in buckets of this size: week or month (computed from the --limit selection)")
+	pulseCmd.Flags().IntVar(&pulsePeriods, "periods", 12, "Number of --interval buckets, ending with the current one")
+	pulseCmd.Flags().StringVar(&pulseGroupBy, "group-by", "", "Show the metrics per label, assignee, author or milestone")
+	pulseCmd.Flags().BoolVar(&pulseFirstResponse, "first-response", false, "Measure the time to the first comment, labeling or assignment by someone other than the author (two extra API requests per issue)")
+	pulseCmd.Flags().BoolVar(&pulseExcludeBots, "exclude-bots", false, "With --first-response, ignore responses by bots")
+	pulseCmd.Flags().BoolVar(&pulseMaintainersOnly, "maintainers-only", false, "With --first-response, only count comments by owners, members and collaborators")
+	pulseCmd.Flags().BoolVar(&pulseDurationsByLabel, "durations-by-label", false, "Break the time to close and open age distributions down by label")
 	rootCmd.AddCommand(pulseCmd)
 }
 
+// pulseFilters describes the non-default filters for the report header.
+func pulseFilters(cmd *cobra.Command) []string {
+	var active []string
+	if cmd.Flags().Changed("label") && pulseLabel != "" {
+		active = append(active, fmt.Sprintf("label=%s", pulseLabel))
+	}
+	if cmd.Flags().Changed("state") && pulseState != "" {
+		active = append(active, fmt.Sprintf("state=%s", pulseState))
+	}
+	if cmd.Flags().Changed("include-prs") && pulseIncludePRs {
+		active = append(active, "include-prs=true")
+	}
+	if cmd.Flags().Changed("created") && pulseCreated != "" {
+		active = append(active, fmt.Sprintf("created=%s", pulseCreated))
+	}
+	if cmd.Flags().Changed("updated") && pulseUpdated != "" {
+		active = append(active, fmt.Sprintf("updated=%s", pulseUpdated))
+	}
+	if cmd.Flags().Changed("closed") && pulseClosed != "" {
+		active = append(active, fmt.Sprintf("closed=%s", pulseClosed))
+	}
+	if cmd.Flags().Changed("limit") && pulseLimit != 100 {
+		active = append(active, fmt.Sprintf("limit=%d", pulseLimit))
+	}
+	return active
+}
+
+// formatDistribution renders the percentiles of d (in days) on one line, in
+// days or hours.
+func formatDistribution(d analyzer.Distribution, unit string) string {
+	f := 1.0
+	if unit == "hours" {
+		f = 24
+	}
+	return fmt.Sprintf("median %.1f / p75 %.1f / p90 %.1f / p95 %.1f / max %.1f %s", d.Median*f, d.P75*f, d.P90*f, d.P95*f, d.Max*f, unit)
+}
+
+// writeGroupsText prints one row of pulse metrics per group. The oldest open
+// issue is shown with its age in days.
+func writeGroupsText(w io.Writer, by string, groups []analyzer.PulseGroup, multiRepo bool) {
+	fmt.Fprintf(w, "By %s%s:\n", strings.ToUpper(by[:1]), by[1:])
+	fmt.Fprintln(w, "  Group\tOpen\tClosed\tTotal\tOpened 7d/30d/90d\tClosed 7d/30d/90d\tMedian close\tOldest open")
+	now := time.Now()
+	for _, g := range groups {
+		m := g.Metrics
+		oldest := "-"
+		if it := g.OldestOpen; it != nil {
+			oldest = fmt.Sprintf("#%d", it.Number)
+			if multiRepo {
+				oldest = fmt.Sprintf("%s#%d", it.Repo, it.Number)
+			}
+			oldest += fmt.Sprintf(" (%dd)", int(now.Sub(it.CreatedAt).Hours()/24))
+		}
+		fmt.Fprintf(w, "  %s\t%d\t%d\t%d\t%d / %d / %d\t%d / %d / %d\t%.1f days\t%s\n",
+			g.Key, m.Open, m.Closed, m.Total,
+			m.Opened7, m.Opened30, m.Opened90,
+			m.Closed7, m.Closed30, m.Closed90,
+			m.TimeToClose.Median, oldest)
+	}
+}
+
+// warnTruncatedTrend warns when the selection a trend is computed from was
+// cut off by --limit: the dropped issues are missing from the opened and
+// closed counts of older periods and from every backlog.
+func warnTruncatedTrend(selected, limit int) {
+	if limit > 0 && selected >= limit {
+		warnf("the trend only counts the %d issues selected by --limit; raise --limit to include every issue opened, closed or still open in the periods shown", selected)
+	}
+}
+
+// trendPeriodLabel names a bucket by its first day (week) or month.
+func trendPeriodLabel(tr *analyzer.Trend, b analyzer.TrendBucket) string {
+	if tr.Interval == "month" {
+		return b.Start.Format("2006-01")
+	}
+	return b.Start.Format("2006-01-02")
+}
+
+// writeTrendText prints one row per bucket with a backlog bar, followed by
+// sparklines of the opened, closed and backlog series.
+func writeTrendText(w io.Writer, tr *analyzer.Trend) {
+	title := "weekly"
+	if tr.Interval == "month" {
+		title = "monthly"
+	}
+	fmt.Fprintf(w, "Trend (%s):\n", title)
+	fmt.Fprintln(w, "  Period\tOpened\tClosed\tNet\tBacklog\tMedian close\t")
+	maxBacklog := 0
+	for _, b := range tr.Buckets {
+		if b.Backlog > maxBacklog {
+			maxBacklog = b.Backlog
+		}
+	}
+	var opened, closed, backlog []int
+	for _, b := range tr.Buckets {
+		fmt.Fprintf(w, "  %s\t%d\t%d\t%+d\t%d\t%.1f days\t%s\n", trendPeriodLabel(tr, b), b.Opened, b.Closed, b.Net, b.Backlog, b.MedianTimeToClose, output.Bar(b.Backlog, maxBacklog, 20))
+		opened = append(opened, b.Opened)
+		closed = append(closed, b.Closed)
+		backlog = append(backlog, b.Backlog)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  Opened:\t%s\n", output.Sparkline(opened))
+	fmt.Fprintf(w, "  Closed:\t%s\n", output.Sparkline(closed))
+	fmt.Fprintf(w, "  Backlog:\t%s\n\n", output.Sparkline(backlog))
+}
+
+// pulseCSVRows flattens the metrics into metric/value rows. Distributions
+// become one row per statistic, and the label, assignee, author and
+// repository counts become rows named like "label:bug", largest first.
+func pulseCSVRows(m analyzer.PulseMetrics) [][]string {
+	var rows [][]string
+	add := func(name string, v string) { rows = append(rows, []string{name, v}) }
+	days := func(f float64) string { return strconv.FormatFloat(f, 'f', 2, 64) }
+	dist := func(prefix string, d analyzer.Distribution) {
+		add(prefix+"_count", strconv.Itoa(d.Count))
+		add(prefix+"_median_days", days(d.Median))
+		add(prefix+"_p75_days", days(d.P75))
+		add(prefix+"_p90_days", days(d.P90))
+		add(prefix+"_p95_days", days(d.P95))
+		add(prefix+"_max_days", days(d.Max))
+	}
+	counts := func(prefix string, c map[string]int) {
+		keys := make([]string, 0, len(c))
+		for k := range c {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if c[keys[i]] != c[keys[j]] {
+				return c[keys[i]] > c[keys[j]]
+			}
+			return keys[i] < keys[j]
+		})
+		for _, k := range keys {
+			add(prefix+":"+k, strconv.Itoa(c[k]))
+		}
+	}
+
+	add("open", strconv.Itoa(m.Open))
+	add("closed", strconv.Itoa(m.Closed))
+	add("total", strconv.Itoa(m.Total))
+	add("opened_7d", strconv.Itoa(m.Opened7))
+	add("opened_30d", strconv.Itoa(m.Opened30))
+	add("opened_90d", strconv.Itoa(m.Opened90))
+	add("closed_7d", strconv.Itoa(m.Closed7))
+	add("closed_30d", strconv.Itoa(m.Closed30))
+	add("closed_90d", strconv.Itoa(m.Closed90))
+	add("avg_days_to_close", days(m.AvgTimeToClose))
+	dist("time_to_close", m.TimeToClose)
+	dist("open_age", m.OpenAge)
+	if m.FirstResponse != nil {
+		dist("first_response", m.FirstResponse.TimeToFirstResponse)
+		add("first_response_unanswered", strconv.Itoa(m.FirstResponse.Unanswered))
+	}
+	counts("label", m.LabelCounts)
+	counts("assignee", m.AssigneeCounts)
+	counts("author", m.AuthorCounts)
+	if len(m.RepoCounts) > 1 {
+		counts("repo", m.RepoCounts)
+	}
+	return rows
+}
+
+var groupCSVHeader = []string{"group", "open", "closed", "total", "opened_7d", "opened_30d", "opened_90d", "closed_7d", "closed_30d", "closed_90d", "median_days_to_close", "oldest_open", "oldest_open_created"}
+
+// groupCSVRows renders one row per --group-by group; oldest_open is
+// owner/repo#N and empty when the group has no open issue.
+func groupCSVRows(groups []analyzer.PulseGroup) [][]string {
+	var rows [][]string
+	for _, g := range groups {
+		m := g.Metrics
+		oldest, oldestCreated := "", ""
+		if it := g.OldestOpen; it != nil {
+			oldest = fmt.Sprintf("%s#%d", it.Repo, it.Number)
+			oldestCreated = it.CreatedAt.UTC().Format(time.RFC3339)
+		}
+		rows = append(rows, []string{
+			g.Key,
+			strconv.Itoa(m.Open), strconv.Itoa(m.Closed), strconv.Itoa(m.Total),
+			strconv.Itoa(m.Opened7), strconv.Itoa(m.Opened30), strconv.Itoa(m.Opened90),
+			strconv.Itoa(m.Closed7), strconv.Itoa(m.Closed30), strconv.Itoa(m.Closed90),
+			strconv.FormatFloat(m.TimeToClose.Median, 'f', 2, 64),
+			oldest, oldestCreated,
+		})
+	}
+	return rows
+}
+
+var trendCSVHeader = []string{"period_start", "period_end", "opened", "closed", "net", "backlog", "median_days_to_close"}
+
+// trendCSVRows renders the trend series; period_end is exclusive.
+func trendCSVRows(tr *analyzer.Trend) [][]string {
+	var rows [][]string
+	for _, b := range tr.Buckets {
+		rows = append(rows, []string{
+			b.Start.Format("2006-01-02"),
+			b.End.Format("2006-01-02"),
+			strconv.Itoa(b.Opened),
+			strconv.Itoa(b.Closed),
+			strconv.Itoa(b.Net),
+			strconv.Itoa(b.Backlog),
+			strconv.FormatFloat(b.MedianTimeToClose, 'f', 1, 64),
+		})
+	}
+	return rows
+}
+
 // truncateString truncates s to max runes and appends an ellipsis if truncated.
 func truncateString(s string, max int) string {
 	if max <= 0 {
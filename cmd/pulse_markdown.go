@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/solvaholic/gh-issue-miner/internal/analyzer"
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+	"github.com/solvaholic/gh-issue-miner/internal/output"
+)
+
+// mdWriter collects the first write error so the report can be written as a
+// sequence of sections without checking each one.
+type mdWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (m *mdWriter) printf(format string, args ...interface{}) {
+	if m.err == nil {
+		_, m.err = fmt.Fprintf(m.w, format, args...)
+	}
+}
+
+// section writes a level-two heading followed by a table.
+func (m *mdWriter) section(title string, header, align []string, rows [][]string) {
+	m.printf("## %s\n\n", title)
+	if m.err == nil {
+		m.err = output.WriteMarkdownTable(m.w, header, align, rows)
+	}
+	m.printf("\n")
+}
+
+// writePulseMarkdown writes the pulse report as GitHub-flavored Markdown: a
+// heading per section of the text output, each with a table. Counts of labels,
+// assignees, authors and repositories carry their share of all issues.
+func writePulseMarkdown(w io.Writer, repoStr string, filters []string, m analyzer.PulseMetrics, groupBy string, groups []analyzer.PulseGroup) error {
+	md := &mdWriter{w: w}
+	multiRepo := len(m.RepoCounts) > 1
+
+	md.printf("# Issue pulse: %s\n\n", output.EscapeMarkdown(repoStr))
+	if len(filters) > 0 {
+		md.printf("Filters: %s\n\n", output.EscapeMarkdown(strings.Join(filters, ", ")))
+	}
+
+	if groupBy != "" {
+		writeGroupsMarkdown(md, groupBy, groups, multiRepo)
+		return md.err
+	}
+
+	md.section("Issues", []string{"State", "Issues", "Share"}, []string{"left", "right", "right"}, [][]string{
+		{"Open", strconv.Itoa(m.Open), percent(m.Open, m.Total)},
+		{"Closed", strconv.Itoa(m.Closed), percent(m.Closed, m.Total)},
+		{"Total", strconv.Itoa(m.Total), percent(m.Total, m.Total)},
+	})
+
+	if multiRepo {
+		md.section("By Repository", []string{"Repository", "Issues", "Share"}, []string{"left", "right", "right"}, shareRows(m.RepoCounts, m.Total, 0))
+	}
+
+	md.section("Activity", []string{"Issues", "7 days", "30 days", "90 days"}, []string{"left", "right", "right", "right"}, [][]string{
+		{"Opened", strconv.Itoa(m.Opened7), strconv.Itoa(m.Opened30), strconv.Itoa(m.Opened90)},
+		{"Closed", strconv.Itoa(m.Closed7), strconv.Itoa(m.Closed30), strconv.Itoa(m.Closed90)},
+	})
+
+	durations := [][]string{
+		distributionRow("Time to close (days)", m.TimeToClose, 1),
+		distributionRow("Open age (days)", m.OpenAge, 1),
+	}
+	if fr := m.FirstResponse; fr != nil {
+		durations = append(durations, distributionRow("First response (hours)", fr.TimeToFirstResponse, 24))
+	}
+	md.section("Durations", []string{"Duration", "Count", "Median", "P75", "P90", "P95", "Max"}, []string{"left", "right", "right", "right", "right", "right", "right"}, durations)
+	md.printf("Average time to close: %.1f days", m.AvgTimeToClose)
+	if fr := m.FirstResponse; fr != nil {
+		md.printf(", %d issues without a response", fr.Unanswered)
+	}
+	md.printf(".\n\n")
+
+	if tr := m.Trend; tr != nil {
+		var rows [][]string
+		for _, b := range tr.Buckets {
+			rows = append(rows, []string{trendPeriodLabel(tr, b), strconv.Itoa(b.Opened), strconv.Itoa(b.Closed), fmt.Sprintf("%+d", b.Net), strconv.Itoa(b.Backlog), fmt.Sprintf("%.1f", b.MedianTimeToClose)})
+		}
+		title := "Weekly Trend"
+		if tr.Interval == "month" {
+			title = "Monthly Trend"
+		}
+		md.section(title, []string{"Period", "Opened", "Closed", "Net", "Backlog", "Median close (days)"}, []string{"left", "right", "right", "right", "right", "right"}, rows)
+	}
+
+	var active [][]string
+	for _, it := range m.TopByComments {
+		active = append(active, []string{issueLink(it, multiRepo), output.EscapeMarkdown(it.Title), strconv.Itoa(it.Comments)})
+	}
+	md.section("Most Active", []string{"Issue", "Title", "Comments"}, []string{"left", "left", "right"}, active)
+
+	md.section("Top Labels", []string{"Label", "Issues", "Share"}, []string{"left", "right", "right"}, shareRows(m.LabelCounts, m.Total, 10))
+
+	if m.LabelDurations != nil {
+		var rows [][]string
+		for _, label := range sortedCountKeys(m.LabelCounts, 10) {
+			d := m.LabelDurations[label]
+			rows = append(rows,
+				append([]string{output.EscapeMarkdown(label)}, distributionRow("time to close", d.TimeToClose, 1)...),
+				append([]string{output.EscapeMarkdown(label)}, distributionRow("open age", d.OpenAge, 1)...))
+		}
+		md.section("Durations by Label (days)", []string{"Label", "Duration", "Count", "Median", "P75", "P90", "P95", "Max"}, []string{"left", "left", "right", "right", "right", "right", "right", "right"}, rows)
+	}
+
+	md.section("Assignees", []string{"Assignee", "Issues", "Share"}, []string{"left", "right", "right"}, shareRows(m.AssigneeCounts, m.Total, 10))
+	if len(m.AuthorCounts) > 1 {
+		md.section("Authors", []string{"Author", "Issues", "Share"}, []string{"left", "right", "right"}, shareRows(m.AuthorCounts, m.Total, 10))
+	}
+	return md.err
+}
+
+// writeGroupsMarkdown renders the --group-by table with the oldest open issue
+// of each group linked.
+func writeGroupsMarkdown(md *mdWriter, by string, groups []analyzer.PulseGroup, multiRepo bool) {
+	now := time.Now()
+	var rows [][]string
+	for _, g := range groups {
+		m := g.Metrics
+		oldest := "-"
+		if it := g.OldestOpen; it != nil {
+			oldest = fmt.Sprintf("%s (%dd)", issueLink(*it, multiRepo), int(now.Sub(it.CreatedAt).Hours()/24))
+		}
+		rows = append(rows, []string{
+			output.EscapeMarkdown(g.Key),
+			strconv.Itoa(m.Open), strconv.Itoa(m.Closed), strconv.Itoa(m.Total),
+			fmt.Sprintf("%d / %d / %d", m.Opened7, m.Opened30, m.Opened90),
+			fmt.Sprintf("%d / %d / %d", m.Closed7, m.Closed30, m.Closed90),
+			fmt.Sprintf("%.1f", m.TimeToClose.Median),
+			oldest,
+		})
+	}
+	header := []string{"Group", "Open", "Closed", "Total", "Opened 7d/30d/90d", "Closed 7d/30d/90d", "Median close (days)", "Oldest open"}
+	align := []string{"left", "right", "right", "right", "right", "right", "right", "left"}
+	md.section("By "+strings.ToUpper(by[:1])+by[1:], header, align, rows)
+}
+
+// issueRef names an issue #N, or owner/repo#N across repositories.
+func issueRef(it api.Issue, multiRepo bool) string {
+	if multiRepo {
+		return fmt.Sprintf("%s#%d", it.Repo, it.Number)
+	}
+	return fmt.Sprintf("#%d", it.Number)
+}
+
+// issueLink renders the issueRef linked to the issue.
+func issueLink(it api.Issue, multiRepo bool) string {
+	return output.MarkdownLink(issueRef(it, multiRepo), it.HTMLURL)
+}
+
+// distributionRow renders name and the statistics of d, scaled from days by f.
+func distributionRow(name string, d analyzer.Distribution, f float64) []string {
+	row := []string{name, strconv.Itoa(d.Count)}
+	for _, v := range []float64{d.Median, d.P75, d.P90, d.P95, d.Max} {
+		row = append(row, fmt.Sprintf("%.1f", v*f))
+	}
+	return row
+}
+
+// shareRows renders up to max counts (all when max is 0), largest first, each
+// with its percentage of total.
+func shareRows(counts map[string]int, total int, max int) [][]string {
+	var rows [][]string
+	for _, k := range sortedCountKeys(counts, max) {
+		rows = append(rows, []string{output.EscapeMarkdown(k), strconv.Itoa(counts[k]), percent(counts[k], total)})
+	}
+	return rows
+}
+
+// sortedCountKeys returns the keys of counts by descending count, then name,
+// limited to max keys unless max is 0.
+func sortedCountKeys(counts map[string]int, max int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if max > 0 && len(keys) > max {
+		keys = keys[:max]
+	}
+	return keys
+}
+
+// percent renders n as a whole percentage of total, as in "40%".
+func percent(n, total int) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.0f%%", float64(n)*100/float64(total))
+}
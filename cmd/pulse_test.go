@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/solvaholic/gh-issue-miner/internal/analyzer"
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+	"github.com/solvaholic/gh-issue-miner/internal/testserver"
+)
+
+func TestTestServer_PulseMarkdown(t *testing.T) {
+	startTestServer(t)
+
+	out, err := runCLI(t, "pulse", "--no-cache", "--repo", "octo/big", "--limit", "200", "--label", "bug", "--format", "markdown")
+	if err != nil {
+		t.Fatalf("pulse markdown: %v", err)
+	}
+	for _, want := range []string{
+		"# Issue pulse: octo/big\n\nFilters: label=bug, limit=200\n",
+		"## Issues\n\n| State | Issues | Share |\n| :--- | ---: | ---: |\n| Open | 40 | 80% |\n| Closed | 10 | 20% |\n| Total | 50 | 100% |\n",
+		"| Opened | 26 | 50 | 50 |",
+		"| Time to close (days) | 10 | ",
+		"| bug | 50 | 100% |",
+		"| unassigned | 50 | 100% |",
+		"| alice | 25 | 50% |",
+		"/octo/big/issues/150) | issue 150 | 0 |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("pulse markdown lacks %q:\n%s", want, out)
+		}
+	}
+
+	out, err = runCLI(t, "pulse", "--no-cache", "--repo", "octo/big", "--limit", "200", "--group-by", "author", "--format", "markdown")
+	if err != nil {
+		t.Fatalf("pulse grouped markdown: %v", err)
+	}
+	if !strings.Contains(out, "## By Author\n") || !strings.Contains(out, "| alice | 60 | 15 | 75 | ") || !strings.Contains(out, "/octo/big/issues/1) (") {
+		t.Fatalf("unexpected grouped markdown:\n%s", out)
+	}
+}
+
+func TestTestServer_Pulse(t *testing.T) {
+	startTestServer(t)
+
+	out, err := runCLI(t, "pulse", "--no-cache", "--repo", "octo/big", "--limit", "200", "--format", "json")
+	if err != nil {
+		t.Fatalf("pulse: %v", err)
+	}
+	if !strings.Contains(out, `"Total": 150`) || !strings.Contains(out, `"Closed": 30`) {
+		t.Fatalf("unexpected pulse output:\n%s", out)
+	}
+
+	// every closed issue took an hour; labels are on every third issue
+	out, err = runCLI(t, "pulse", "--no-cache", "--repo", "octo/big", "--limit", "200", "--durations-by-label", "--format", "json")
+	if err != nil {
+		t.Fatalf("pulse --durations-by-label: %v", err)
+	}
+	var res struct {
+		Metrics analyzer.PulseMetrics `json:"metrics"`
+	}
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		t.Fatalf("pulse output is not JSON: %v", err)
+	}
+	m := res.Metrics
+	if m.TimeToClose.Count != 30 || m.TimeToClose.P95*24 < 0.99 || m.TimeToClose.Max*24 > 1.01 || m.OpenAge.Count != 120 {
+		t.Fatalf("unexpected distributions: %+v / %+v", m.TimeToClose, m.OpenAge)
+	}
+	if bug := m.LabelDurations["bug"]; bug.TimeToClose.Count != 10 || bug.OpenAge.Count != 40 {
+		t.Fatalf("unexpected bug durations: %+v", bug)
+	}
+
+	// issues alternate between alice and bob; issue 1 (alice) is the oldest
+	out, err = runCLI(t, "pulse", "--no-cache", "--repo", "octo/big", "--limit", "200", "--group-by", "author", "--format", "json")
+	if err != nil {
+		t.Fatalf("pulse --group-by: %v", err)
+	}
+	var grouped struct {
+		GroupBy string                `json:"group_by"`
+		Groups  []analyzer.PulseGroup `json:"groups"`
+	}
+	if err := json.Unmarshal([]byte(out), &grouped); err != nil {
+		t.Fatalf("pulse output is not JSON: %v", err)
+	}
+	if grouped.GroupBy != "author" || len(grouped.Groups) != 2 || grouped.Groups[0].Key != "alice" || grouped.Groups[0].Metrics.Total != 75 || grouped.Groups[0].OldestOpen.Number != 1 {
+		t.Fatalf("unexpected groups:\n%s", out)
+	}
+	out, err = runCLI(t, "pulse", "--no-cache", "--repo", "octo/big", "--limit", "200", "--group-by", "label")
+	if err != nil {
+		t.Fatalf("pulse --group-by text: %v", err)
+	}
+	if !strings.Contains(out, "By Label:") || !strings.Contains(out, "  bug ") || strings.Contains(out, "Most Active:") {
+		t.Fatalf("unexpected grouped text:\n%s", out)
+	}
+}
+
+func TestTestServer_PulseTabularFormats(t *testing.T) {
+	startTestServer(t)
+
+	out, err := runCLI(t, "pulse", "--no-cache", "--repo", "octo/big", "--limit", "200", "--format", "csv")
+	if err != nil {
+		t.Fatalf("pulse csv: %v", err)
+	}
+	if !strings.HasPrefix(out, "metric,value\nopen,120\nclosed,30\ntotal,150\n") || !strings.Contains(out, "\nlabel:bug,50\n") || !strings.Contains(out, "\ntime_to_close_count,30\n") {
+		t.Fatalf("unexpected pulse csv:\n%s", out)
+	}
+	out, err = runCLI(t, "pulse", "--no-cache", "--repo", "octo/big", "--limit", "200", "--group-by", "author", "--format", "tsv")
+	if err != nil {
+		t.Fatalf("pulse grouped tsv: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 3 || !strings.HasPrefix(lines[1], "alice\t") || !strings.Contains(lines[1], "\tocto/big#1\t") {
+		t.Fatalf("unexpected pulse grouped tsv:\n%s", out)
+	}
+}
+
+func TestTestServer_PulseTemplate(t *testing.T) {
+	startTestServer(t)
+
+	// a template file, pulse metrics and the duration helper
+	file := filepath.Join(t.TempDir(), "slack.tmpl")
+	if err := os.WriteFile(file, []byte(`*{{.repository}}*: {{.metrics.Open}} open, median close {{duration .metrics.TimeToClose.Median}}{{if gt (int .metrics.Open) 100}} :fire:{{end}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runCLI(t, "pulse", "--no-cache", "--repo", "octo/big", "--limit", "200", "--template", file)
+	if err != nil {
+		t.Fatalf("pulse --template: %v", err)
+	}
+	if out != "*octo/big*: 120 open, median close 1h :fire:" {
+		t.Fatalf("unexpected pulse template output: %q", out)
+	}
+}
+
+func TestTestServer_PulseHTML(t *testing.T) {
+	startTestServer(t)
+
+	out, err := runCLI(t, "pulse", "--no-cache", "--repo", "octo/big", "--limit", "200", "--format", "html")
+	if err != nil {
+		t.Fatalf("pulse html: %v", err)
+	}
+	if !strings.Contains(out, "<title>Issue pulse</title>") || strings.Contains(out, "graphData") || strings.Contains(out, "Weekly Trend") {
+		t.Fatalf("unexpected pulse html:\n%s", out)
+	}
+}
+
+func TestTestServer_PulseTrend(t *testing.T) {
+	startTestServer(t)
+	var warnings bytes.Buffer
+	oldWarn := warnOut
+	warnOut = &warnings
+	defer func() { warnOut = oldWarn }()
+
+	// all 150 issues were created in the last 10 days, so three weeks cover them
+	out, err := runCLI(t, "pulse", "--no-cache", "--repo", "octo/big", "--limit", "200", "--interval", "week", "--periods", "3", "--format", "csv")
+	if err != nil {
+		t.Fatalf("pulse csv: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not CSV: %v\n%s", err, out)
+	}
+	if len(records) != 4 || strings.Join(records[0], ",") != "period_start,period_end,opened,closed,net,backlog,median_days_to_close" {
+		t.Fatalf("unexpected CSV:\n%s", out)
+	}
+	opened, closed := 0, 0
+	for _, r := range records[1:] {
+		o, _ := strconv.Atoi(r[2])
+		c, _ := strconv.Atoi(r[3])
+		opened += o
+		closed += c
+	}
+	if opened != 150 || closed != 30 || records[3][5] != "120" {
+		t.Fatalf("expected 150 opened, 30 closed and a backlog of 120, got %d, %d, %s\n%s", opened, closed, records[3][5], out)
+	}
+	if warnings.Len() != 0 {
+		t.Fatalf("unexpected warning for a complete selection: %s", warnings.String())
+	}
+
+	out, err = runCLI(t, "pulse", "--no-cache", "--repo", "octo/big", "--interval", "month", "--periods", "2")
+	if err != nil {
+		t.Fatalf("pulse text: %v", err)
+	}
+	if !strings.Contains(out, "Trend (monthly):") || !strings.Contains(out, "Backlog:") {
+		t.Fatalf("expected a monthly trend section:\n%s", out)
+	}
+	// the default --limit of 100 cuts off the older half of the issues
+	if !strings.Contains(warnings.String(), "the trend only counts the 100 issues selected by --limit") {
+		t.Fatalf("expected a truncation warning, got %q", warnings.String())
+	}
+
+	if _, err := runCLI(t, "pulse", "--no-cache", "--repo", "octo/big", "--interval", "day"); err == nil {
+		t.Fatalf("expected an unknown interval to fail")
+	}
+
+	// the trend table would drop the other metrics, so csv refuses them
+	srv := startTestServer(t)
+	_, err = runCLI(t, "pulse", "--no-cache", "--repo", "octo/big", "--interval", "week", "--durations-by-label", "--format", "csv")
+	if err == nil || !strings.Contains(err.Error(), "writes only the trend") {
+		t.Fatalf("expected --interval with --durations-by-label to be rejected for csv, got %v", err)
+	}
+	if len(srv.Requests()) != 0 {
+		t.Fatalf("expected no API requests, got %v", srv.Requests())
+	}
+}
+
+func TestTestServer_PulseFirstResponse(t *testing.T) {
+	srv := testserver.New()
+	t.Cleanup(srv.Close)
+	base := time.Now().UTC().Add(-72 * time.Hour).Truncate(time.Second)
+	at := func(h int) time.Time { return base.Add(time.Duration(h) * time.Hour) }
+	srv.AddIssues("octo/help",
+		testserver.Issue{Number: 1, Author: "alice", CreatedAt: at(0), Comments: []testserver.Comment{
+			{ID: 11, Author: "alice", CreatedAt: at(1), AuthorAssociation: "NONE"},
+			{ID: 12, Author: "helper[bot]", CreatedAt: at(2), AuthorAssociation: "NONE"},
+			{ID: 13, Author: "dave", CreatedAt: at(4), AuthorAssociation: "CONTRIBUTOR"},
+			{ID: 14, Author: "bob", CreatedAt: at(8), AuthorAssociation: "MEMBER"},
+		}},
+		testserver.Issue{Number: 2, Author: "carol", CreatedAt: at(0), Timeline: []testserver.Event{
+			{ID: 21, Event: "labeled", Actor: "bob", CreatedAt: at(6)},
+		}},
+		testserver.Issue{Number: 3, Author: "carol", CreatedAt: at(0)},
+	)
+	orig := api.Settings.BaseURL
+	api.Settings.BaseURL = srv.URL()
+	t.Cleanup(func() { api.Settings.BaseURL = orig })
+
+	firstResponse := func(args ...string) *analyzer.ResponseMetrics {
+		t.Helper()
+		out, err := runCLI(t, append([]string{"pulse", "--no-cache", "--repo", "octo/help", "--first-response", "--format", "json"}, args...)...)
+		if err != nil {
+			t.Fatalf("pulse %v: %v", args, err)
+		}
+		var res struct {
+			Metrics analyzer.PulseMetrics `json:"metrics"`
+		}
+		if err := json.Unmarshal([]byte(out), &res); err != nil || res.Metrics.FirstResponse == nil {
+			t.Fatalf("pulse %v: unexpected output (%v):\n%s", args, err, out)
+		}
+		return res.Metrics.FirstResponse
+	}
+	hours := func(d float64) int { return int(d*24 + 0.5) }
+
+	// issue 1 answered by the bot after 2h, issue 2 labeled after 6h
+	if fr := firstResponse(); fr.Unanswered != 1 || hours(fr.TimeToFirstResponse.Median) != 4 || hours(fr.TimeToFirstResponse.Max) != 6 {
+		t.Fatalf("unexpected first response: %+v", fr)
+	}
+	if fr := firstResponse("--exclude-bots"); hours(fr.TimeToFirstResponse.Median) != 5 {
+		t.Fatalf("unexpected first response without bots: %+v", fr)
+	}
+	if fr := firstResponse("--exclude-bots", "--maintainers-only"); hours(fr.TimeToFirstResponse.Median) != 7 || hours(fr.TimeToFirstResponse.Max) != 8 {
+		t.Fatalf("unexpected first response by maintainers: %+v", fr)
+	}
+
+	if _, err := runCLI(t, "pulse", "--no-cache", "--repo", "octo/help", "--exclude-bots"); err == nil {
+		t.Fatalf("expected --exclude-bots without --first-response to fail")
+	}
+}
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/solvaholic/gh-issue-miner/internal/analyzer"
+	"github.com/solvaholic/gh-issue-miner/internal/output"
+)
+
+var reportRepos repoSelection
+var reportLimit int
+var reportIncludePRs bool
+var reportLabel string
+var reportState string
+var reportAssignee string
+var reportAuthor string
+var reportCreated string
+var reportUpdated string
+var reportClosed string
+var reportInterval string
+var reportPeriods int
+var reportGraph bool
+var reportDepth int
+var reportCrossRepo bool
+var reportMaxNodes int
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Write a self-contained HTML report of the pulse metrics and reference graph",
+	Long: `Write a single HTML file with the pulse metrics, opened/closed and backlog
+trend charts, label, assignee and author distributions, and an interactive
+view of the reference graph of the selected issues (drag to pan, scroll to
+zoom, click a node to open the issue).
+
+Styles, data and scripts are inline, so the file opens without a server or
+network access and can be attached to an email or kept as a CI artifact.`,
+	Example: `  gh issue-miner report --repo cli/cli --limit 1000 --output report.html
+  gh issue-miner report --org myorg --interval month --graph=false --output report.html`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext(cmd)
+
+		if outputFormat != "html" && cmd.Flags().Changed("format") {
+			return fmt.Errorf("invalid --format value for report: %s (allowed: html)", outputFormat)
+		}
+		if reportInterval != "week" && reportInterval != "month" {
+			return fmt.Errorf("invalid --interval value: %s (allowed: week, month)", reportInterval)
+		}
+
+		repos, err := reportRepos.resolve(ctx)
+		if err != nil {
+			return err
+		}
+		issues, repoStr, err := FetchIssuesMulti(ctx, repos, reportLimit, reportIncludePRs, reportLabel, reportState, reportAssignee, reportAuthor, reportCreated, reportUpdated, reportClosed, "", "")
+		if err != nil {
+			return err
+		}
+
+		metrics := analyzer.ComputePulse(issues)
+		warnTruncatedTrend(len(issues), reportLimit)
+		trend, err := analyzer.ComputeTrend(issues, reportInterval, reportPeriods, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		metrics.Trend = &trend
+
+		var g *issueGraph
+		graphOpts := graphOptions{Depth: reportDepth, CrossRepo: reportCrossRepo, MaxNodes: reportMaxNodes}
+		if reportGraph {
+			g = buildGraph(ctx, nil, "", issues, graphOpts)
+		}
+
+		// prepare output writer (stdout or file)
+		var out io.Writer = os.Stdout
+		if outputFile != "" {
+			f, err := os.Create(outputFile)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+
+		if outputTemplate != nil {
+			doc := map[string]interface{}{"repository": repoStr, "metrics": metrics}
+			if g != nil {
+				doc["graph"] = graphDocument(repoStr, g, graphOpts)
+			}
+			return output.WriteTemplate(out, outputTemplate, doc)
+		}
+
+		report := pulseHTMLReport(repoStr, reportFilters(cmd), metrics, "", nil)
+		report.Title = "Issue report"
+		if g != nil {
+			report.Graph = graphModel(g)
+		}
+		if err := output.WriteHTMLReport(out, report); err != nil {
+			return err
+		}
+		if g != nil && g.Interrupted {
+			return fmt.Errorf("interrupted: wrote partial graph (%d of %d discovered nodes visited)", len(g.Nodes), g.Discovered)
+		}
+		return nil
+	},
+}
+
+func init() {
+	addRepoFlags(reportCmd, &reportRepos)
+	reportCmd.Flags().IntVar(&reportLimit, "limit", 500, "Maximum number of issues to analyze")
+	reportCmd.Flags().BoolVar(&reportIncludePRs, "include-prs", false, "Include pull requests in results")
+	reportCmd.Flags().StringVar(&reportLabel, "label", "", "Comma-separated label specs (exact, prefix*, or -excluded). Matches issues containing any of these labels")
+	reportCmd.Flags().StringVar(&reportState, "state", "", "Filter by issue state: open, closed")
+	reportCmd.Flags().StringVar(&reportAssignee, "assignee", "", "Filter by assignee username")
+	reportCmd.Flags().StringVar(&reportAuthor, "author", "", "Filter by issue author username")
+	reportCmd.Flags().StringVar(&reportCreated, "created", "", "Filter by created timeframe (e.g., 7d, 2025-01-01, 2025-01-01..2025-01-31)")
+	reportCmd.Flags().StringVar(&reportUpdated, "updated", "", "Filter by updated timeframe (e.g., 7d, 2025-01-01)")
+	reportCmd.Flags().StringVar(&reportClosed, "closed", "", "Filter by closed timeframe (e.g., 30d, 2025-01-01..2025-02-01)")
+	reportCmd.Flags().StringVar(&reportInterval, "interval", "week", "Trend bucket size: week or month")
+	reportCmd.Flags().IntVar(&reportPeriods, "periods", 12, "Number of trend buckets, ending with the current one")
+	reportCmd.Flags().BoolVar(&reportGraph, "graph", true, "Include the reference graph (one or two API requests per issue); --graph=false skips it")
+	reportCmd.Flags().IntVar(&reportDepth, "depth", 1, "Graph traversal depth for following references")
+	reportCmd.Flags().BoolVar(&reportCrossRepo, "cross-repo", false, "Allow the graph to follow references across repositories")
+	reportCmd.Flags().IntVar(&reportMaxNodes, "max-nodes", 500, "Maximum number of graph nodes to visit (0 = unlimited)")
+	rootCmd.AddCommand(reportCmd)
+}
+
+// reportFilters describes the non-default selection filters of report.
+func reportFilters(cmd *cobra.Command) []string {
+	var active []string
+	for _, name := range []string{"label", "state", "assignee", "author", "created", "updated", "closed", "limit"} {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			active = append(active, name+"="+f.Value.String())
+		}
+	}
+	if reportIncludePRs {
+		active = append(active, "include-prs=true")
+	}
+	return active
+}
+
+// pulseHTMLReport lays the pulse metrics out as an HTML report: headline
+// numbers, the trend when computed, count distributions and tables. With
+// groupBy, the per-group table replaces the single pulse.
+func pulseHTMLReport(repoStr string, filters []string, m analyzer.PulseMetrics, groupBy string, groups []analyzer.PulseGroup) output.HTMLReport {
+	multiRepo := len(m.RepoCounts) > 1
+	r := output.HTMLReport{
+		Title:      "Issue pulse",
+		Repository: repoStr,
+		Generated:  time.Now(),
+		Filters:    filters,
+		Stats: []output.HTMLStat{
+			{Label: "Open", Value: strconv.Itoa(m.Open), Detail: percent(m.Open, m.Total) + " of " + strconv.Itoa(m.Total)},
+			{Label: "Closed", Value: strconv.Itoa(m.Closed), Detail: percent(m.Closed, m.Total) + " of " + strconv.Itoa(m.Total)},
+			{Label: "Opened in 30 days", Value: strconv.Itoa(m.Opened30), Detail: fmt.Sprintf("7d: %d, 90d: %d", m.Opened7, m.Opened90)},
+			{Label: "Closed in 30 days", Value: strconv.Itoa(m.Closed30), Detail: fmt.Sprintf("7d: %d, 90d: %d", m.Closed7, m.Closed90)},
+			{Label: "Median time to close", Value: fmt.Sprintf("%.1f days", m.TimeToClose.Median), Detail: fmt.Sprintf("p90: %.1f days", m.TimeToClose.P90)},
+			{Label: "Median open age", Value: fmt.Sprintf("%.1f days", m.OpenAge.Median), Detail: fmt.Sprintf("p90: %.1f days", m.OpenAge.P90)},
+		},
+	}
+	if fr := m.FirstResponse; fr != nil {
+		r.Stats = append(r.Stats, output.HTMLStat{Label: "Median first response", Value: fmt.Sprintf("%.1f hours", fr.TimeToFirstResponse.Median*24), Detail: fmt.Sprintf("%d unanswered", fr.Unanswered)})
+	}
+
+	if groupBy != "" {
+		now := time.Now()
+		t := output.HTMLTable{
+			Title:   "By " + strings.ToUpper(groupBy[:1]) + groupBy[1:],
+			Header:  []string{"Group", "Open", "Closed", "Total", "Opened 7d/30d/90d", "Closed 7d/30d/90d", "Median close (days)", "Oldest open"},
+			Numeric: []bool{false, true, true, true, true, true, true, false},
+		}
+		for _, g := range groups {
+			gm := g.Metrics
+			oldest := output.HTMLCell{Text: "-"}
+			if it := g.OldestOpen; it != nil {
+				oldest = output.HTMLCell{Text: fmt.Sprintf("%s (%dd)", issueRef(*it, multiRepo), int(now.Sub(it.CreatedAt).Hours()/24)), URL: it.HTMLURL}
+			}
+			t.Rows = append(t.Rows, []output.HTMLCell{
+				{Text: g.Key},
+				{Text: strconv.Itoa(gm.Open)}, {Text: strconv.Itoa(gm.Closed)}, {Text: strconv.Itoa(gm.Total)},
+				{Text: fmt.Sprintf("%d / %d / %d", gm.Opened7, gm.Opened30, gm.Opened90)},
+				{Text: fmt.Sprintf("%d / %d / %d", gm.Closed7, gm.Closed30, gm.Closed90)},
+				{Text: fmt.Sprintf("%.1f", gm.TimeToClose.Median)},
+				oldest,
+			})
+		}
+		r.Tables = append(r.Tables, t)
+		return r
+	}
+
+	if tr := m.Trend; tr != nil {
+		t := &output.HTMLTrend{Title: "Weekly Trend"}
+		if tr.Interval == "month" {
+			t.Title = "Monthly Trend"
+		}
+		for _, b := range tr.Buckets {
+			t.Periods = append(t.Periods, output.HTMLPeriod{Label: trendPeriodLabel(tr, b), Opened: b.Opened, Closed: b.Closed, Backlog: b.Backlog})
+		}
+		r.Trend = t
+	}
+
+	bars := func(title string, counts map[string]int, max int) output.HTMLBars {
+		b := output.HTMLBars{Title: title, Total: m.Total}
+		for _, k := range sortedCountKeys(counts, max) {
+			b.Bars = append(b.Bars, output.HTMLBar{Label: k, Count: counts[k]})
+		}
+		return b
+	}
+	if multiRepo {
+		r.Bars = append(r.Bars, bars("Repositories", m.RepoCounts, 0))
+	}
+	r.Bars = append(r.Bars, bars("Top Labels", m.LabelCounts, 10), bars("Assignees", m.AssigneeCounts, 10))
+	if len(m.AuthorCounts) > 1 {
+		r.Bars = append(r.Bars, bars("Authors", m.AuthorCounts, 10))
+	}
+
+	durations := output.HTMLTable{
+		Title:   "Durations",
+		Header:  []string{"Duration", "Count", "Median", "P75", "P90", "P95", "Max"},
+		Numeric: []bool{false, true, true, true, true, true, true},
+		Rows: [][]output.HTMLCell{
+			htmlCells(distributionRow("Time to close (days)", m.TimeToClose, 1)),
+			htmlCells(distributionRow("Open age (days)", m.OpenAge, 1)),
+		},
+	}
+	if fr := m.FirstResponse; fr != nil {
+		durations.Rows = append(durations.Rows, htmlCells(distributionRow("First response (hours)", fr.TimeToFirstResponse, 24)))
+	}
+	r.Tables = append(r.Tables, durations)
+
+	if m.LabelDurations != nil {
+		t := output.HTMLTable{
+			Title:   "Durations by Label (days)",
+			Header:  []string{"Label", "Duration", "Count", "Median", "P75", "P90", "P95", "Max"},
+			Numeric: []bool{false, false, true, true, true, true, true, true},
+		}
+		for _, label := range sortedCountKeys(m.LabelCounts, 10) {
+			d := m.LabelDurations[label]
+			t.Rows = append(t.Rows,
+				htmlCells(append([]string{label}, distributionRow("time to close", d.TimeToClose, 1)...)),
+				htmlCells(append([]string{label}, distributionRow("open age", d.OpenAge, 1)...)))
+		}
+		r.Tables = append(r.Tables, t)
+	}
+
+	active := output.HTMLTable{Title: "Most Active", Header: []string{"Issue", "Title", "Comments"}, Numeric: []bool{false, false, true}}
+	for _, it := range m.TopByComments {
+		active.Rows = append(active.Rows, []output.HTMLCell{{Text: issueRef(it, multiRepo), URL: it.HTMLURL}, {Text: it.Title}, {Text: strconv.Itoa(it.Comments)}})
+	}
+	r.Tables = append(r.Tables, active)
+	return r
+}
+
+// htmlCells turns plain text cells into table cells.
+func htmlCells(texts []string) []output.HTMLCell {
+	cells := make([]output.HTMLCell, len(texts))
+	for i, t := range texts {
+		cells[i] = output.HTMLCell{Text: t}
+	}
+	return cells
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTestServer_Report(t *testing.T) {
+	startTestServer(t)
+
+	out, err := runCLI(t, "report", "--no-cache", "--repo", "octo/big", "--limit", "200")
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	if !strings.HasPrefix(out, "<!DOCTYPE html>") || strings.Contains(out, "<script src") || strings.Contains(out, "<link") {
+		t.Fatalf("report is not a self-contained HTML document:\n%.300s", out)
+	}
+	for _, want := range []string{
+		"<title>Issue report</title>",
+		"<h2>Weekly Trend</h2>",
+		`<svg class="chart"`,
+		`<td class="name">bug</td>`,
+		`<td class="num">33%</td>`,
+		`{"id":"octo/big#1","repo":"octo/big","number":1,"title":"issue 1","state":"open","url":"http://`,
+		`{"from":"octo/big#1","to":"octo/big#2","source":"timeline","actor":"alice","action":"cross-referenced",`,
+		"<script>// Draws graphData",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("report lacks %q", want)
+		}
+	}
+	if _, err := runCLI(t, "report", "--no-cache", "--repo", "octo/big", "--format", "json"); err == nil {
+		t.Fatalf("expected report --format json to fail")
+	}
+}
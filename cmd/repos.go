@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+	"github.com/solvaholic/gh-issue-miner/internal/util"
+)
+
+// repoSelection holds the flags that choose the repositories fetch, pulse and graph read.
+type repoSelection struct {
+	repos           []string
+	org             string
+	include         []string
+	exclude         []string
+	includeArchived bool
+}
+
+// repoFlagNames lists the flags registered by addRepoFlags.
+var repoFlagNames = []string{"repo", "org", "include-repos", "exclude-repos", "include-archived"}
+
+// addRepoFlags registers the repository selection flags on cmd.
+func addRepoFlags(cmd *cobra.Command, sel *repoSelection) {
+	cmd.Flags().StringSliceVar(&sel.repos, "repo", nil, "Repository in [HOST/]OWNER/REPO format; repeat, comma-separate or use a glob such as owner/* (default: current repo)")
+	cmd.Flags().StringVar(&sel.org, "org", "", "Select every repository of this organization ([HOST/]ORG)")
+	cmd.Flags().StringSliceVar(&sel.include, "include-repos", nil, "With --org or a --repo glob, only select repositories whose name matches one of these globs")
+	cmd.Flags().StringSliceVar(&sel.exclude, "exclude-repos", nil, "With --org or a --repo glob, skip repositories whose name matches one of these globs")
+	cmd.Flags().BoolVar(&sel.includeArchived, "include-archived", false, "With --org or a --repo glob, also select archived repositories")
+}
+
+// changedRepoFlags returns the repository selection flags set on cmd, as --name.
+func changedRepoFlags(cmd *cobra.Command) []string {
+	var out []string
+	for _, name := range repoFlagNames {
+		if cmd.Flags().Changed(name) {
+			out = append(out, "--"+name)
+		}
+	}
+	return out
+}
+
+// resolve returns the selected repositories in the form FetchIssues expects
+// (owner/repo, or HOST/OWNER/REPO off the default host), without duplicates.
+// Without --repo and --org the current repository is detected.
+func (sel repoSelection) resolve(ctx context.Context) ([]string, error) {
+	for _, p := range append(append([]string(nil), sel.include...), sel.exclude...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid repository pattern %q: %w", p, err)
+		}
+	}
+	if len(sel.repos) == 0 && sel.org == "" {
+		host, repo, err := util.DetectRepo("")
+		if err != nil {
+			return nil, err
+		}
+		return []string{api.QualifyRepo(host, repo)}, nil
+	}
+
+	var out []string
+	seen := map[string]bool{}
+	add := func(repo string) {
+		if k := strings.ToLower(repo); !seen[k] {
+			seen[k] = true
+			out = append(out, repo)
+		}
+	}
+	for _, r := range sel.repos {
+		host, repo := util.SplitRepo(strings.TrimSpace(r))
+		owner, name, ok := strings.Cut(repo, "/")
+		if !ok || owner == "" || name == "" {
+			return nil, fmt.Errorf("invalid --repo value %q: expected [HOST/]OWNER/REPO", r)
+		}
+		if !strings.ContainsAny(name, "*?[") {
+			add(api.QualifyRepo(host, repo))
+			continue
+		}
+		if _, err := path.Match(name, ""); err != nil {
+			return nil, fmt.Errorf("invalid --repo pattern %q: %w", r, err)
+		}
+		matched, err := sel.ownerRepos(ctx, host, owner, name)
+		if err != nil {
+			return nil, err
+		}
+		if len(matched) == 0 {
+			return nil, fmt.Errorf("no repositories match --repo %s", r)
+		}
+		for _, m := range matched {
+			add(m)
+		}
+	}
+	if sel.org != "" {
+		host, org := "", sel.org
+		if i := strings.LastIndex(org, "/"); i >= 0 {
+			host, org = strings.ToLower(org[:i]), org[i+1:]
+		}
+		matched, err := sel.ownerRepos(ctx, host, org, "*")
+		if err != nil {
+			return nil, err
+		}
+		if len(matched) == 0 {
+			return nil, fmt.Errorf("no repositories selected in organization %s", sel.org)
+		}
+		for _, m := range matched {
+			add(m)
+		}
+	}
+	return out, nil
+}
+
+// ownerRepos lists the repositories of owner on host whose name matches
+// pattern and the include/exclude globs, skipping archived ones unless asked.
+func (sel repoSelection) ownerRepos(ctx context.Context, host, owner, pattern string) ([]string, error) {
+	client, err := api.ClientForHost(host)
+	if err != nil {
+		return nil, err
+	}
+	repos, err := api.ListOwnerRepos(ctx, client, owner)
+	if err != nil {
+		return nil, fmt.Errorf("list repositories of %s: %w", owner, err)
+	}
+	var out []string
+	for _, r := range repos {
+		if r.Archived && !sel.includeArchived {
+			continue
+		}
+		name := strings.ToLower(r.Name)
+		if ok, _ := path.Match(strings.ToLower(pattern), name); !ok {
+			continue
+		}
+		if len(sel.include) > 0 && !matchAny(sel.include, name) {
+			continue
+		}
+		if matchAny(sel.exclude, name) {
+			continue
+		}
+		full := r.FullName
+		if full == "" {
+			full = owner + "/" + r.Name
+		}
+		out = append(out, api.QualifyRepo(host, full))
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(strings.ToLower(strings.TrimSpace(p)), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchRepoWorkers bounds how many repositories are listed at once, matching
+// the graph and sync worker pools.
+const fetchRepoWorkers = 5
+
+// FetchIssuesMulti runs FetchIssues for every repository in repos (a few at a
+// time, each with a client for its host) and returns the union with Repo set
+// on every issue. With several repositories the union is ordered by
+// sort/direction like a single listing (newest created first by default) and
+// trimmed to limit, with a warning naming the repositories left without
+// issues. The returned name lists the repositories, comma-separated.
+func FetchIssuesMulti(ctx context.Context, repos []string, limit int, includePRs bool, label string, state string, assignee string, author string, created string, updated string, closed string, sortField string, direction string) ([]api.Issue, string, error) {
+	results := make([][]api.Issue, len(repos))
+	errs := make([]error, len(repos))
+	sem := make(chan struct{}, fetchRepoWorkers)
+	var wg sync.WaitGroup
+	for i, r := range repos {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int, r string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			client, repoArg, err := repoClient(r)
+			if err == nil {
+				results[i], _, err = FetchIssues(ctx, client, repoArg, limit, includePRs, label, state, assignee, author, created, updated, closed, sortField, direction)
+			}
+			if err != nil && len(repos) > 1 {
+				err = fmt.Errorf("%s: %w", r, err)
+			}
+			errs[i] = err
+		}(i, r)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+	var issues []api.Issue
+	for i := range repos {
+		if errs[i] != nil {
+			return nil, "", errs[i]
+		}
+		issues = append(issues, results[i]...)
+	}
+	if len(repos) > 1 {
+		sortIssues(issues, sortField, direction)
+		if limit > 0 && len(issues) > limit {
+			warnf("the %d issues selected from %d repositories were cut to %d by --limit; raise --limit to cover every repository%s", len(issues), len(repos), limit, missingRepos(repos, issues[:limit]))
+			issues = issues[:limit]
+		}
+	}
+	return issues, strings.Join(repos, ", "), nil
+}
+
+// missingRepos names the repositories of repos without an issue in issues,
+// for the warning of FetchIssuesMulti; it returns "" when none is missing.
+func missingRepos(repos []string, issues []api.Issue) string {
+	seen := map[string]bool{}
+	for _, it := range issues {
+		seen[it.Repo] = true
+	}
+	var missing []string
+	for _, r := range repos {
+		if !seen[r] {
+			missing = append(missing, r)
+		}
+	}
+	if len(missing) == 0 {
+		return ""
+	}
+	return " (no issues left from " + strings.Join(missing, ", ") + ")"
+}
+
+// sortIssues orders issues like the list endpoint: by created (default),
+// updated or comments, newest/most first unless direction is asc.
+func sortIssues(issues []api.Issue, field, direction string) {
+	asc := strings.EqualFold(direction, "asc")
+	before := func(a, b api.Issue) bool {
+		switch field {
+		case "updated":
+			return a.UpdatedAt.Before(b.UpdatedAt)
+		case "comments":
+			return a.Comments < b.Comments
+		default:
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if asc {
+			return before(issues[i], issues[j])
+		}
+		return before(issues[j], issues[i])
+	})
+}
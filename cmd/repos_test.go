@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+func TestTestServer_OrgAndRepoGlobs(t *testing.T) {
+	startOrgServer(t)
+
+	fetch := func(args ...string) []api.Issue {
+		t.Helper()
+		out, err := runCLI(t, append([]string{"fetch", "--no-cache", "--format", "json"}, args...)...)
+		if err != nil {
+			t.Fatalf("fetch %v: %v", args, err)
+		}
+		var res struct {
+			Issues []api.Issue `json:"issues"`
+		}
+		if err := json.Unmarshal([]byte(out), &res); err != nil {
+			t.Fatalf("fetch %v: output is not JSON: %v", args, err)
+		}
+		return res.Issues
+	}
+	names := func(issues []api.Issue) string {
+		var s []string
+		for _, it := range issues {
+			s = append(s, fmt.Sprintf("%s#%d", it.Repo, it.Number))
+		}
+		return strings.Join(s, " ")
+	}
+
+	// the union is ordered newest first across repositories; archived repos are skipped
+	if got, want := names(fetch("--org", "octo")), "octo/a#3 octo/b#2 octo/a#2 octo/b#1 octo/a#1"; got != want {
+		t.Fatalf("--org octo: got %s, want %s", got, want)
+	}
+	if got := fetch("--repo", "octo/*", "--include-archived"); len(got) != 6 {
+		t.Fatalf("expected archived octo/old to be included, got %s", names(got))
+	}
+	if got, want := names(fetch("--org", "octo", "--exclude-repos", "b", "--limit", "2")), "octo/a#3 octo/a#2"; got != want {
+		t.Fatalf("--exclude-repos b: got %s, want %s", got, want)
+	}
+	if got, want := names(fetch("--repo", "octo/b", "--repo", "octo/old", "--sort", "created", "--direction", "asc")), "octo/old#1 octo/b#1 octo/b#2"; got != want {
+		t.Fatalf("repeated --repo: got %s, want %s", got, want)
+	}
+	// cutting the union to --limit is reported, with the repositories it left out
+	var warnings bytes.Buffer
+	oldWarn := warnOut
+	warnOut = &warnings
+	got := names(fetch("--org", "octo", "--limit", "1"))
+	warnOut = oldWarn
+	if got != "octo/a#3" || !strings.Contains(warnings.String(), "the 2 issues selected from 2 repositories were cut to 1 by --limit") || !strings.Contains(warnings.String(), "(no issues left from octo/b)") {
+		t.Fatalf("--limit 1: got %s, warnings %q", got, warnings.String())
+	}
+	if _, err := runCLI(t, "fetch", "--no-cache", "--repo", "octo/z*"); err == nil || !strings.Contains(err.Error(), "no repositories match") {
+		t.Fatalf("expected an error for a glob without matches, got %v", err)
+	}
+
+	out, err := runCLI(t, "pulse", "--no-cache", "--org", "octo")
+	if err != nil {
+		t.Fatalf("pulse: %v", err)
+	}
+	if !strings.Contains(out, "By Repository:") || !strings.Contains(out, "octo/a  3") {
+		t.Fatalf("expected a per-repository breakdown:\n%s", out)
+	}
+
+	out, err = runCLI(t, "graph", "--no-cache", "--org", "octo", "--format", "dot")
+	if err != nil {
+		t.Fatalf("graph: %v", err)
+	}
+	if !strings.Contains(out, `"octo/a#1" -> "octo/b#2"`) {
+		t.Fatalf("missing edge between repositories of the selection:\n%s", out)
+	}
+}
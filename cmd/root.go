@@ -1,33 +1,158 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"text/template"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+	"github.com/solvaholic/gh-issue-miner/internal/output"
+	"github.com/solvaholic/gh-issue-miner/internal/store"
 )
 
 var outputFormat string
 var outputFile string
+var outputTemplateArg string
+
+// outputTemplate is the parsed --template, or nil to use --format.
+var outputTemplate *template.Template
+var selectionBackend string
+
+var cacheEnabled bool
+var cacheDisabled bool
+var cacheTTL time.Duration
+
+var offlineMode bool
+var storeDir string
+
+var recordDir string
+var replayDir string
+
+var hostname string
+
+// warnOut receives the warnings of the commands; tests replace it.
+var warnOut io.Writer = os.Stderr
+
+// warnf writes a warning line to warnOut.
+func warnf(format string, args ...interface{}) {
+	fmt.Fprintf(warnOut, "warning: "+format+"\n", args...)
+}
 
 var rootCmd = &cobra.Command{
 	Use:   "issue-miner",
 	Short: "Analyze GitHub issues",
 	Long:  "issue-miner: metrics and graphs for GitHub issues (gh extension)",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if cacheTTL < 0 {
+			return fmt.Errorf("invalid --cache-ttl value: %s (must not be negative)", cacheTTL)
+		}
+		api.Settings.Cache = cacheEnabled && !cacheDisabled
+		api.Settings.CacheTTL = cacheTTL
+		api.Settings.Host = hostname
+		outputTemplate = nil
+		if outputTemplateArg != "" {
+			if cmd.Flags().Changed("format") {
+				return fmt.Errorf("--template cannot be combined with --format")
+			}
+			t, err := output.ParseTemplate(outputTemplateArg)
+			if err != nil {
+				return err
+			}
+			outputTemplate = t
+		}
+		if offlineMode {
+			if selectionBackend == "search" {
+				return fmt.Errorf("--backend search is not available with --offline")
+			}
+			st, err := openStore()
+			if err != nil {
+				return err
+			}
+			// serve every API call from the store; listing falls back to REST
+			// because the offline client does not implement GraphQL
+			api.NewClient = func() (api.RESTClient, error) { return store.NewClient(st, api.DefaultHost()), nil }
+			api.NewHostClient = func(host string) (api.RESTClient, error) { return store.NewClient(st, host), nil }
+		}
+		if replayDir != "" {
+			if offlineMode || recordDir != "" {
+				return fmt.Errorf("--replay cannot be combined with --offline or --record")
+			}
+			rc, err := api.NewReplayClient(replayDir)
+			if err != nil {
+				return err
+			}
+			// fixtures are keyed by path, so every host is served from the same set
+			api.NewClient = func() (api.RESTClient, error) { return rc, nil }
+			api.NewHostClient = func(string) (api.RESTClient, error) { return rc, nil }
+		}
+		if recordDir != "" {
+			newClient := api.NewClient
+			api.NewClient = func() (api.RESTClient, error) {
+				c, err := newClient()
+				if err != nil {
+					return nil, err
+				}
+				return api.NewRecordingClient(c, recordDir)
+			}
+			newHostClient := api.NewHostClient
+			api.NewHostClient = func(host string) (api.RESTClient, error) {
+				c, err := newHostClient(host)
+				if err != nil {
+					return nil, err
+				}
+				return api.NewRecordingClient(c, recordDir)
+			}
+		}
+		return nil
+	},
 }
 
-// Execute runs the root command.
+// Execute runs the root command. The first Ctrl-C cancels the command context
+// so long-running commands can stop and write partial results; a second one
+// terminates the process.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
 
+// commandContext returns the context the command was executed with, or
+// context.Background() when RunE is invoked directly (as tests do).
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
 func init() {
 	// Global output flags (Phase 3)
-	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "text", "Output format (text, json, dot)")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", "text", "Output format (text, json, dot, mermaid, graphml, gexf, markdown, csv, tsv, html)")
 	rootCmd.PersistentFlags().StringVar(&outputFile, "output", "", "Output file (default: stdout)")
+	rootCmd.PersistentFlags().StringVar(&outputTemplateArg, "template", "", "Render the output with a Go text/template, given as a file name or inline; it sees the data of --format json")
+	rootCmd.PersistentFlags().BoolVar(&cacheEnabled, "cache", true, "Cache API responses on disk and revalidate them with ETags")
+	rootCmd.PersistentFlags().BoolVar(&cacheDisabled, "no-cache", false, "Disable the on-disk response cache")
+	rootCmd.PersistentFlags().DurationVar(&cacheTTL, "cache-ttl", 0, "Reuse cached responses for this long without contacting the API (e.g., 10m, 1h; 0 = always revalidate REST responses and do not cache GraphQL listings)")
+	rootCmd.PersistentFlags().BoolVar(&offlineMode, "offline", false, "Read issues from the local store written by sync instead of the GitHub API")
+	rootCmd.PersistentFlags().StringVar(&storeDir, "store", "", "Local store directory for sync and --offline (default: $XDG_DATA_HOME/gh-issue-miner or ~/.local/share/gh-issue-miner)")
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "Save every API response as a fixture file in this directory (for tests and bug reports)")
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "Serve API responses from fixtures saved with --record instead of the GitHub API")
+	rootCmd.PersistentFlags().StringVar(&hostname, "hostname", "", "GitHub host for repositories given as owner/repo, e.g. a GitHub Enterprise Server (default: GH_HOST or gh's default host)")
+	rootCmd.PersistentFlags().StringVar(&selectionBackend, "backend", "list", "Issue selection backend: list (GraphQL/REST listing) or search (exact server-side filters; at most 1000 results per repository and 30 requests per minute)")
 
 	// Add subcommands
 	rootCmd.AddCommand(fetchCmd)
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+// buildSearchQuery translates selection filters into a GitHub issue search query.
+// Label specs are comma-separated: plain and prefix* specs are ORed together,
+// and specs starting with '-' exclude issues carrying that label. Prefix specs
+// are expanded against the repository labels; prefixes that match no label are
+// kept literally so the query still selects nothing for them.
+// Time filters use the same syntax as filterIssues and become exact >= / < bounds.
+func buildSearchQuery(ctx context.Context, client api.RESTClient, repo string, includePRs bool, label string, state string, assignee string, author string, created string, updated string, closed string) (string, error) {
+	terms := []string{"repo:" + repo}
+	if !includePRs {
+		terms = append(terms, "is:issue")
+	}
+	if state != "" && !strings.EqualFold(state, "all") {
+		terms = append(terms, "state:"+strings.ToLower(state))
+	}
+	if assignee != "" {
+		terms = append(terms, "assignee:"+assignee)
+	}
+	if author != "" {
+		terms = append(terms, "author:"+author)
+	}
+
+	labelTerms, err := searchLabelTerms(ctx, client, repo, label)
+	if err != nil {
+		return "", err
+	}
+	terms = append(terms, labelTerms...)
+
+	for _, f := range []struct {
+		qualifier string
+		raw       string
+	}{
+		{"created", created},
+		{"updated", updated},
+		{"closed", closed},
+	} {
+		start, end, err := parseTimeRange(f.raw)
+		if err != nil {
+			return "", err
+		}
+		if start != nil {
+			terms = append(terms, fmt.Sprintf("%s:>=%s", f.qualifier, start.UTC().Format(time.RFC3339)))
+		}
+		if end != nil {
+			terms = append(terms, fmt.Sprintf("%s:<%s", f.qualifier, end.UTC().Format(time.RFC3339)))
+		}
+	}
+
+	return strings.Join(terms, " "), nil
+}
+
+// searchLabelTerms returns the label qualifiers for a raw label spec list:
+// one "label:a,b" term for the ORed specs and one "-label:x" term per exclusion.
+func searchLabelTerms(ctx context.Context, client api.RESTClient, repo string, raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+
+	var include, exclude []string
+	for _, part := range strings.Split(raw, ",") {
+		p := strings.TrimSpace(part)
+		if p == "" {
+			continue
+		}
+		if strings.HasPrefix(p, "-") {
+			exclude = append(exclude, strings.TrimPrefix(p, "-"))
+		} else {
+			include = append(include, p)
+		}
+	}
+
+	var repoLabels []string
+	for _, p := range append(append([]string{}, include...), exclude...) {
+		if strings.HasSuffix(p, "*") {
+			rl, err := api.ListRepoLabels(ctx, client, repo)
+			if err != nil {
+				return nil, err
+			}
+			repoLabels = rl
+			break
+		}
+	}
+	expand := func(specs []string) []string {
+		var out []string
+		for _, p := range specs {
+			if !strings.HasSuffix(p, "*") {
+				out = append(out, p)
+				continue
+			}
+			prefix := strings.TrimSuffix(p, "*")
+			found := false
+			for _, rl := range repoLabels {
+				if strings.HasPrefix(rl, prefix) {
+					out = append(out, rl)
+					found = true
+				}
+			}
+			if !found {
+				out = append(out, p)
+			}
+		}
+		return out
+	}
+
+	var terms []string
+	if inc := expand(include); len(inc) > 0 {
+		quoted := make([]string, len(inc))
+		for i, l := range inc {
+			quoted[i] = quoteSearchValue(l)
+		}
+		terms = append(terms, "label:"+strings.Join(quoted, ","))
+	}
+	for _, l := range expand(exclude) {
+		terms = append(terms, "-label:"+quoteSearchValue(l))
+	}
+	return terms, nil
+}
+
+// quoteSearchValue wraps values containing spaces or search syntax in double quotes.
+func quoteSearchValue(s string) string {
+	s = strings.ReplaceAll(s, "\"", "")
+	if strings.ContainsAny(s, " ,:") {
+		return "\"" + s + "\""
+	}
+	return s
+}
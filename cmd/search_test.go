@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+func TestBuildSearchQuery(t *testing.T) {
+	fake := &fakeRESTClient{responses: map[string]interface{}{
+		"repos/o/r/labels": []map[string]interface{}{
+			{"name": "area/api"},
+			{"name": "area/cli"},
+			{"name": "bug"},
+		},
+	}}
+
+	q, err := buildSearchQuery(context.Background(), fake, "o/r", false, "bug,area/*,-wontfix,-help wanted", "open", "alice", "bob", "2025-01-01..2025-01-31", "", "2025-02-01..")
+	if err != nil {
+		t.Fatalf("buildSearchQuery returned error: %v", err)
+	}
+	want := []string{
+		"repo:o/r",
+		"is:issue",
+		"state:open",
+		"assignee:alice",
+		"author:bob",
+		"label:bug,area/api,area/cli",
+		"-label:wontfix",
+		`-label:"help wanted"`,
+		"created:>=2025-01-01T00:00:00Z",
+		"created:<2025-02-01T00:00:00Z",
+		"closed:>=2025-02-01T00:00:00Z",
+	}
+	if q != strings.Join(want, " ") {
+		t.Fatalf("unexpected query:\n got: %s\nwant: %s", q, strings.Join(want, " "))
+	}
+}
+
+func TestBuildSearchQuery_UnmatchedPrefixKeptLiteral(t *testing.T) {
+	fake := &fakeRESTClient{responses: map[string]interface{}{
+		"repos/o/r/labels": []map[string]interface{}{{"name": "bug"}},
+	}}
+	q, err := buildSearchQuery(context.Background(), fake, "o/r", true, "nope-*", "all", "", "", "", "", "")
+	if err != nil {
+		t.Fatalf("buildSearchQuery returned error: %v", err)
+	}
+	if q != "repo:o/r label:nope-*" {
+		t.Fatalf("unexpected query: %s", q)
+	}
+}
+
+func TestFetchIssues_SearchBackend(t *testing.T) {
+	oldBackend := selectionBackend
+	oldSearch := api.SearchIssuesFunc
+	oldList := api.ListIssuesFunc
+	defer func() {
+		selectionBackend = oldBackend
+		api.SearchIssuesFunc = oldSearch
+		api.ListIssuesFunc = oldList
+	}()
+
+	selectionBackend = "search"
+	api.ListIssuesFunc = nil
+	var gotQuery string
+	var gotLimit int
+	api.SearchIssuesFunc = func(ctx context.Context, client api.RESTClient, query string, limit int, sort string, direction string) ([]api.Issue, error) {
+		gotQuery = query
+		gotLimit = limit
+		return []api.Issue{{Number: 1}}, nil
+	}
+
+	out, repo, err := FetchIssues(context.Background(), nil, "o/r", 5, false, "bug", "closed", "", "", "", "", "", "", "")
+	if err != nil {
+		t.Fatalf("FetchIssues returned error: %v", err)
+	}
+	if repo != "o/r" || len(out) != 1 {
+		t.Fatalf("unexpected result: %q %v", repo, out)
+	}
+	if gotLimit != 5 {
+		t.Fatalf("search backend must not over-fetch candidates, got limit %d", gotLimit)
+	}
+	if gotQuery != "repo:o/r is:issue state:closed label:bug" {
+		t.Fatalf("unexpected query: %s", gotQuery)
+	}
+}
+
+func TestFetchIssues_SearchBackendWarnsAboveCap(t *testing.T) {
+	oldBackend, oldSearch, oldWarn := selectionBackend, api.SearchIssuesFunc, warnOut
+	defer func() {
+		selectionBackend, api.SearchIssuesFunc, warnOut = oldBackend, oldSearch, oldWarn
+	}()
+
+	selectionBackend = "search"
+	var warnings bytes.Buffer
+	warnOut = &warnings
+	api.SearchIssuesFunc = func(ctx context.Context, client api.RESTClient, query string, limit int, sort string, direction string) ([]api.Issue, error) {
+		return nil, nil
+	}
+
+	if _, _, err := FetchIssues(context.Background(), nil, "o/r", 1000, false, "", "", "", "", "", "", "", "", ""); err != nil {
+		t.Fatalf("FetchIssues returned error: %v", err)
+	}
+	if warnings.Len() != 0 {
+		t.Fatalf("unexpected warning at the cap: %q", warnings.String())
+	}
+	if _, _, err := FetchIssues(context.Background(), nil, "o/r", 2500, false, "", "", "", "", "", "", "", "", ""); err != nil {
+		t.Fatalf("FetchIssues returned error: %v", err)
+	}
+	if !strings.Contains(warnings.String(), "--limit 2500 is reduced to 1000 for o/r") {
+		t.Fatalf("expected a cap warning, got %q", warnings.String())
+	}
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/solvaholic/gh-issue-miner/internal/analyzer"
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+	"github.com/solvaholic/gh-issue-miner/internal/output"
+)
+
+var staleRepos repoSelection
+var staleDays int
+var staleLimit int
+var staleScan int
+var staleIncludePRs bool
+var staleLabel string
+var staleAssignee string
+var staleAuthor string
+var staleCreated string
+var staleUpdated string
+var staleExcludeBots bool
+
+var staleCmd = &cobra.Command{
+	Use:   "stale",
+	Short: "List open issues without recent activity",
+	Long: `List open issues whose last comment or timeline event is at least --days
+old, most stale first. For each issue, show the last activity and who did it,
+the days since a maintainer (owner, member or collaborator) last commented,
+and whether the author spoke last, meaning the issue is waiting on the
+maintainers.
+
+The least recently updated open issues are examined first, --limit at a time,
+and their comments and timelines are fetched to find the last activity. By
+default only --limit issues are examined. Bot comments and events bump the
+update time, so with --exclude-bots an issue kept fresh by a bot sorts late;
+raise --scan to keep examining until --limit stale issues are found.`,
+	Example: `  gh issue-miner stale --repo cli/cli --days 60 --label bug
+  gh issue-miner stale --org myorg --exclude-bots --scan 1000 --format csv > triage.csv`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := commandContext(cmd)
+
+		switch outputFormat {
+		case "text", "json", "csv", "tsv":
+		default:
+			return fmt.Errorf("invalid --format value for stale: %s (allowed: text, json, csv, tsv)", outputFormat)
+		}
+		if staleDays < 0 {
+			return fmt.Errorf("--days must not be negative")
+		}
+
+		repos, err := staleRepos.resolve(ctx)
+		if err != nil {
+			return err
+		}
+		// the least recently updated issues are the likeliest to be stale; up
+		// to --scan of them are examined, --limit at a time, until --limit
+		// stale issues are found
+		scan := staleScan
+		if scan < staleLimit {
+			scan = staleLimit
+		}
+		candidates, repoStr, err := FetchIssuesMulti(ctx, repos, scan, staleIncludePRs, staleLabel, "open", staleAssignee, staleAuthor, staleCreated, staleUpdated, "", "updated", "asc")
+		if err != nil {
+			return err
+		}
+		batch := staleLimit
+		if batch <= 0 {
+			batch = len(candidates)
+		}
+		now := time.Now()
+		var clients hostClients
+		var issues []api.Issue
+		var activity [][]analyzer.Activity
+		var stale []analyzer.StaleIssue
+		for len(issues) < len(candidates) && (staleLimit <= 0 || len(stale) < staleLimit) {
+			next := candidates[len(issues):min(len(issues)+batch, len(candidates))]
+			acts, err := fetchActivity(ctx, &clients, next)
+			if err != nil {
+				return err
+			}
+			issues = append(issues, next...)
+			activity = append(activity, acts...)
+			stale = analyzer.ComputeStale(issues, activity, staleDays, now, analyzer.StaleOptions{ExcludeBots: staleExcludeBots})
+		}
+		if staleLimit > 0 && len(stale) > staleLimit {
+			stale = stale[:staleLimit]
+		}
+
+		// prepare output writer (stdout or file)
+		var out io.Writer = os.Stdout
+		if outputFile != "" {
+			f, err := os.Create(outputFile)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+
+		if outputTemplate != nil {
+			return output.WriteTemplate(out, outputTemplate, output.FetchDocument(repoStr, stale))
+		}
+
+		switch outputFormat {
+		case "json":
+			return output.WriteFetchJSON(out, repoStr, stale)
+		case "csv", "tsv":
+			return output.WriteTable(out, outputFormat, staleCSVHeader, staleCSVRows(stale))
+		}
+
+		if len(repos) > 1 {
+			fmt.Fprintf(out, "Repositories:\t%s\n", repoStr)
+		} else {
+			fmt.Fprintf(out, "Repository:\t%s\n", repoStr)
+		}
+		fmt.Fprintf(out, "Stale:\t%d of %d open issues without activity for %d days\n\n", len(stale), len(issues), staleDays)
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "#\tdays\tlast activity\tmaintainer reply\twaiting on us\ttitle")
+		for _, s := range stale {
+			number := strconv.Itoa(s.Number)
+			if len(repos) > 1 {
+				number = fmt.Sprintf("%s#%d", s.Repo, s.Number)
+			}
+			last := s.LastActivityType
+			if s.LastActivityActor != "" {
+				last += " by " + s.LastActivityActor
+			}
+			reply := "never"
+			if s.DaysSinceMaintainerReply != nil {
+				reply = fmt.Sprintf("%dd ago", *s.DaysSinceMaintainerReply)
+			}
+			waiting := "no"
+			if s.AuthorSpokeLast {
+				waiting = "yes"
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t\"%s\"\n", number, s.DaysStale, last, reply, waiting, s.Title)
+		}
+		return w.Flush()
+	},
+}
+
+var staleCSVHeader = []string{"repo", "number", "title", "author", "last_activity_at", "last_activity_type", "last_activity_actor", "days_stale", "days_since_maintainer_reply", "author_spoke_last", "url"}
+
+// staleCSVRows renders stale issues for CSV; days_since_maintainer_reply is
+// empty when no maintainer ever replied.
+func staleCSVRows(stale []analyzer.StaleIssue) [][]string {
+	var rows [][]string
+	for _, s := range stale {
+		reply := ""
+		if s.DaysSinceMaintainerReply != nil {
+			reply = strconv.Itoa(*s.DaysSinceMaintainerReply)
+		}
+		rows = append(rows, []string{
+			s.Repo,
+			strconv.Itoa(s.Number),
+			s.Title,
+			s.Author,
+			s.LastActivityAt.UTC().Format(time.RFC3339),
+			s.LastActivityType,
+			s.LastActivityActor,
+			strconv.Itoa(s.DaysStale),
+			reply,
+			strconv.FormatBool(s.AuthorSpokeLast),
+			s.URL,
+		})
+	}
+	return rows
+}
+
+func init() {
+	addRepoFlags(staleCmd, &staleRepos)
+	staleCmd.Flags().IntVar(&staleDays, "days", 30, "Minimum number of days without activity")
+	staleCmd.Flags().IntVar(&staleLimit, "limit", 100, "Maximum number of stale issues to list, and of open issues to examine at a time")
+	staleCmd.Flags().IntVar(&staleScan, "scan", 0, "Examine up to this many of the least recently updated open issues until --limit stale ones are found (default: --limit)")
+	staleCmd.Flags().BoolVar(&staleIncludePRs, "include-prs", false, "Include pull requests in results")
+	staleCmd.Flags().StringVar(&staleLabel, "label", "", "Comma-separated label specs (exact, prefix*, or -excluded). Matches issues containing any of these labels")
+	staleCmd.Flags().StringVar(&staleAssignee, "assignee", "", "Filter by assignee username")
+	staleCmd.Flags().StringVar(&staleAuthor, "author", "", "Filter by issue author username")
+	staleCmd.Flags().StringVar(&staleCreated, "created", "", "Filter by created timeframe (e.g., 7d, 2025-01-01, 2025-01-01..2025-01-31)")
+	staleCmd.Flags().StringVar(&staleUpdated, "updated", "", "Filter by updated timeframe (e.g., ..60d, 2025-01-01)")
+	staleCmd.Flags().BoolVar(&staleExcludeBots, "exclude-bots", false, "Do not count comments and events by bots as activity; bot activity still sorts an issue late, so raise --scan to examine it")
+	rootCmd.AddCommand(staleCmd)
+}
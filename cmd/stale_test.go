@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+	"github.com/solvaholic/gh-issue-miner/internal/testserver"
+)
+
+func TestTestServer_Stale(t *testing.T) {
+	srv := testserver.New()
+	t.Cleanup(srv.Close)
+	ago := func(days int) time.Time { return time.Now().UTC().AddDate(0, 0, -days).Truncate(time.Second) }
+	closed := ago(1)
+	srv.AddIssues("octo/triage",
+		testserver.Issue{Number: 1, Title: "waiting", Author: "alice", CreatedAt: ago(100), UpdatedAt: ago(40), Comments: []testserver.Comment{
+			{ID: 11, Author: "bob", CreatedAt: ago(60), AuthorAssociation: "MEMBER"},
+			{ID: 12, Author: "alice", CreatedAt: ago(40), AuthorAssociation: "NONE"},
+		}},
+		testserver.Issue{Number: 2, Title: "fresh", Author: "alice", CreatedAt: ago(100), UpdatedAt: ago(2), Timeline: []testserver.Event{
+			{ID: 21, Event: "assigned", Actor: "bob", CreatedAt: ago(2)},
+		}},
+		testserver.Issue{Number: 3, Title: "forgotten", Author: "carol", CreatedAt: ago(90)},
+		testserver.Issue{Number: 4, Title: "done", Author: "carol", CreatedAt: ago(90), State: "closed", ClosedAt: &closed},
+		// a stale bot keeps bumping the update time
+		testserver.Issue{Number: 5, Title: "bumped", Author: "dave", CreatedAt: ago(120), UpdatedAt: ago(1), Comments: []testserver.Comment{
+			{ID: 51, Author: "stale[bot]", CreatedAt: ago(1), AuthorAssociation: "NONE"},
+		}},
+	)
+	orig := api.Settings.BaseURL
+	api.Settings.BaseURL = srv.URL()
+	t.Cleanup(func() { api.Settings.BaseURL = orig })
+
+	out, err := runCLI(t, "stale", "--no-cache", "--repo", "octo/triage", "--days", "30", "--format", "csv")
+	if err != nil {
+		t.Fatalf("stale: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil || len(records) != 3 {
+		t.Fatalf("expected two stale issues (%v):\n%s", err, out)
+	}
+	// most stale first: #3 was never touched, #1 waits on a reply to alice
+	if records[1][1] != "3" || records[1][5] != "opened" || records[1][8] != "" || records[1][9] != "true" {
+		t.Fatalf("unexpected first row: %v", records[1])
+	}
+	if records[2][1] != "1" || records[2][5] != "comment" || records[2][6] != "alice" || records[2][7] != "40" || records[2][8] != "60" || records[2][9] != "true" {
+		t.Fatalf("unexpected second row: %v", records[2])
+	}
+
+	out, err = runCLI(t, "stale", "--no-cache", "--repo", "octo/triage", "--days", "50")
+	if err != nil {
+		t.Fatalf("stale text: %v", err)
+	}
+	if !strings.Contains(out, "1 of 4 open issues without activity for 50 days") || !strings.Contains(out, `"forgotten"`) || strings.Contains(out, `"waiting"`) {
+		t.Fatalf("unexpected text output:\n%s", out)
+	}
+
+	// only the least recently updated issue is examined without --scan
+	out, err = runCLI(t, "stale", "--no-cache", "--repo", "octo/triage", "--days", "100", "--limit", "1", "--exclude-bots")
+	if err != nil {
+		t.Fatalf("stale --exclude-bots: %v", err)
+	}
+	if !strings.Contains(out, "0 of 1 open issues") {
+		t.Fatalf("unexpected text output:\n%s", out)
+	}
+	out, err = runCLI(t, "stale", "--no-cache", "--repo", "octo/triage", "--days", "100", "--limit", "1", "--exclude-bots", "--scan", "10")
+	if err != nil {
+		t.Fatalf("stale --scan: %v", err)
+	}
+	if !strings.Contains(out, "1 of 4 open issues") || !strings.Contains(out, `"bumped"`) {
+		t.Fatalf("expected --scan to find the bot-bumped issue:\n%s", out)
+	}
+}
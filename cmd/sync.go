@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+	"github.com/solvaholic/gh-issue-miner/internal/output"
+	"github.com/solvaholic/gh-issue-miner/internal/store"
+	"github.com/solvaholic/gh-issue-miner/internal/util"
+)
+
+var syncRepo string
+var syncFull bool
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Mirror a repository's issues into the local store for --offline use",
+	Long: `Download issues, pull requests, comments, labels and timeline events of a
+repository into the local store. After the first run only issues updated since
+the previous sync are downloaded. Use --offline with fetch, pulse and graph to
+analyze the stored data without network access.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if offlineMode {
+			return fmt.Errorf("sync cannot run with --offline")
+		}
+		ctx := commandContext(cmd)
+		host, repo, err := util.DetectRepo(syncRepo)
+		if err != nil {
+			return err
+		}
+		if host == "" {
+			host = api.DefaultHost()
+		}
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		client, err := api.ClientForHost(host)
+		if err != nil {
+			return err
+		}
+		res, err := store.Sync(ctx, client, st, host, repo, syncFull)
+		if err != nil {
+			return err
+		}
+		if outputTemplate != nil {
+			return output.WriteTemplate(cmd.OutOrStdout(), outputTemplate, map[string]interface{}{"repository": store.Key(host, repo), "store": st.Dir(), "result": res})
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "synced %s: %d updated, %d issues and pull requests stored, %d labels (%s)\n", store.Key(host, repo), res.Updated, res.Total, res.Labels, st.Dir())
+		return nil
+	},
+}
+
+// openStore opens the store at --store, or the default location.
+func openStore() (*store.Store, error) {
+	dir := storeDir
+	if dir == "" {
+		d, err := store.DefaultDir()
+		if err != nil {
+			return nil, fmt.Errorf("locate store directory: %w; set --store", err)
+		}
+		dir = d
+	}
+	return store.Open(dir)
+}
+
+func init() {
+	syncCmd.Flags().StringVar(&syncRepo, "repo", "", "Repository in [HOST/]OWNER/REPO format (default: current repo)")
+	syncCmd.Flags().BoolVar(&syncFull, "full", false, "Download everything again instead of only changes since the last sync")
+	rootCmd.AddCommand(syncCmd)
+}
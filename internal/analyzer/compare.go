@@ -0,0 +1,109 @@
+package analyzer
+
+import "sort"
+
+// Comparison holds pulse metrics of several selections side by side. The
+// first column is the baseline; Deltas are relative to it.
+type Comparison struct {
+	Columns []ComparisonColumn `json:"columns"`
+	Rows    []ComparisonRow    `json:"rows"`
+}
+
+// ComparisonColumn is one compared selection (a repository or a time window).
+type ComparisonColumn struct {
+	Name    string       `json:"name"`
+	Metrics PulseMetrics `json:"metrics"`
+}
+
+// ComparisonRow is one metric across all columns. Deltas[0] is always 0.
+type ComparisonRow struct {
+	Metric    string    `json:"metric"`
+	Values    []float64 `json:"values"`
+	Deltas    []float64 `json:"deltas"`
+	Precision int       `json:"-"` // decimals to show: 0 for counts, 1 for days
+}
+
+// Compare builds the comparison table for metrics, one column per name. The
+// label rows cover the union of the topLabels most used labels of each column.
+func Compare(names []string, metrics []PulseMetrics, topLabels int) Comparison {
+	var c Comparison
+	for i, n := range names {
+		c.Columns = append(c.Columns, ComparisonColumn{Name: n, Metrics: metrics[i]})
+	}
+	add := func(name string, precision int, value func(PulseMetrics) float64) {
+		row := ComparisonRow{Metric: name, Precision: precision}
+		for _, m := range metrics {
+			v := value(m)
+			row.Values = append(row.Values, v)
+			row.Deltas = append(row.Deltas, v-value(metrics[0]))
+		}
+		c.Rows = append(c.Rows, row)
+	}
+	count := func(f func(PulseMetrics) int) func(PulseMetrics) float64 {
+		return func(m PulseMetrics) float64 { return float64(f(m)) }
+	}
+
+	add("Open", 0, count(func(m PulseMetrics) int { return m.Open }))
+	add("Closed", 0, count(func(m PulseMetrics) int { return m.Closed }))
+	add("Total", 0, count(func(m PulseMetrics) int { return m.Total }))
+	add("Opened 7d", 0, count(func(m PulseMetrics) int { return m.Opened7 }))
+	add("Opened 30d", 0, count(func(m PulseMetrics) int { return m.Opened30 }))
+	add("Opened 90d", 0, count(func(m PulseMetrics) int { return m.Opened90 }))
+	add("Closed 7d", 0, count(func(m PulseMetrics) int { return m.Closed7 }))
+	add("Closed 30d", 0, count(func(m PulseMetrics) int { return m.Closed30 }))
+	add("Closed 90d", 0, count(func(m PulseMetrics) int { return m.Closed90 }))
+	add("Avg time to close (days)", 1, func(m PulseMetrics) float64 { return m.AvgTimeToClose })
+	add("Median time to close (days)", 1, func(m PulseMetrics) float64 { return m.TimeToClose.Median })
+	add("P90 time to close (days)", 1, func(m PulseMetrics) float64 { return m.TimeToClose.P90 })
+	add("Median open age (days)", 1, func(m PulseMetrics) float64 { return m.OpenAge.Median })
+
+	for _, l := range unionTopLabels(metrics, topLabels) {
+		label := l
+		add("Label: "+label, 0, count(func(m PulseMetrics) int { return m.LabelCounts[label] }))
+	}
+	return c
+}
+
+// unionTopLabels returns the labels among the n most used of any column,
+// ordered by their total count and then by name.
+func unionTopLabels(metrics []PulseMetrics, n int) []string {
+	total := map[string]int{}
+	picked := map[string]bool{}
+	for _, m := range metrics {
+		for _, l := range topKeys(m.LabelCounts, n) {
+			picked[l] = true
+		}
+		for l, v := range m.LabelCounts {
+			total[l] += v
+		}
+	}
+	var out []string
+	for l := range picked {
+		out = append(out, l)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if total[out[i]] != total[out[j]] {
+			return total[out[i]] > total[out[j]]
+		}
+		return out[i] < out[j]
+	})
+	return out
+}
+
+// topKeys returns the n keys with the highest counts, ties broken by name.
+func topKeys(counts map[string]int, n int) []string {
+	var keys []string
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if n >= 0 && len(keys) > n {
+		keys = keys[:n]
+	}
+	return keys
+}
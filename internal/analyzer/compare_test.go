@@ -0,0 +1,32 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompare_DeltasAndLabelUnion(t *testing.T) {
+	a := PulseMetrics{Open: 4, Closed: 1, Total: 5, LabelCounts: map[string]int{"bug": 3, "docs": 1}}
+	b := PulseMetrics{Open: 2, Closed: 3, Total: 5, LabelCounts: map[string]int{"feature": 2, "docs": 1}}
+	c := Compare([]string{"o/a", "o/b"}, []PulseMetrics{a, b}, 1)
+
+	if len(c.Columns) != 2 || c.Columns[1].Name != "o/b" {
+		t.Fatalf("unexpected columns: %+v", c.Columns)
+	}
+	open := c.Rows[0]
+	if open.Metric != "Open" || !reflect.DeepEqual(open.Values, []float64{4, 2}) || !reflect.DeepEqual(open.Deltas, []float64{0, -2}) {
+		t.Fatalf("unexpected Open row: %+v", open)
+	}
+
+	// the top label of each column, not the overall top two
+	var labels []string
+	for _, r := range c.Rows {
+		if l, ok := strings.CutPrefix(r.Metric, "Label: "); ok {
+			labels = append(labels, l)
+		}
+	}
+	if !reflect.DeepEqual(labels, []string{"bug", "feature"}) {
+		t.Fatalf("unexpected label rows: %v", labels)
+	}
+}
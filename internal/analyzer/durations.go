@@ -0,0 +1,107 @@
+package analyzer
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+// Distribution summarizes a set of durations in days. All fields are 0 when
+// Count is 0.
+type Distribution struct {
+	Count  int
+	Median float64
+	P75    float64
+	P90    float64
+	P95    float64
+	Max    float64
+}
+
+// LabelDurations holds the time to close and open age distributions of the
+// issues carrying one label.
+type LabelDurations struct {
+	TimeToClose Distribution
+	OpenAge     Distribution
+}
+
+// NewDistribution computes the distribution of days. Percentiles interpolate
+// linearly between the closest ranks, so the median of an even count is the
+// mean of the middle two. days is sorted in place.
+func NewDistribution(days []float64) Distribution {
+	if len(days) == 0 {
+		return Distribution{}
+	}
+	sort.Float64s(days)
+	return Distribution{
+		Count:  len(days),
+		Median: percentile(days, 50),
+		P75:    percentile(days, 75),
+		P90:    percentile(days, 90),
+		P95:    percentile(days, 95),
+		Max:    days[len(days)-1],
+	}
+}
+
+// percentile returns the p-th percentile of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
+
+// closeDays returns how long a closed issue took to close, in days. ok is
+// false for open issues and for bad data (closed before it was created).
+func closeDays(it api.Issue) (float64, bool) {
+	if it.ClosedAt == nil {
+		return 0, false
+	}
+	d := it.ClosedAt.Sub(it.CreatedAt)
+	if d <= 0 {
+		return 0, false
+	}
+	return d.Hours() / 24.0, true
+}
+
+// openDays returns the age of an open issue at now, in days.
+func openDays(it api.Issue, now time.Time) (float64, bool) {
+	if it.State != "open" {
+		return 0, false
+	}
+	d := now.Sub(it.CreatedAt)
+	if d < 0 {
+		d = 0
+	}
+	return d.Hours() / 24.0, true
+}
+
+// ComputeLabelDurations returns the time to close and open age distributions
+// of every label. An issue with several labels counts for each of them.
+func ComputeLabelDurations(issues []api.Issue, now time.Time) map[string]LabelDurations {
+	closed := map[string][]float64{}
+	open := map[string][]float64{}
+	labels := map[string]bool{}
+	for _, it := range issues {
+		c, isClosed := closeDays(it)
+		o, isOpen := openDays(it, now)
+		for _, l := range it.Labels {
+			labels[l] = true
+			if isClosed {
+				closed[l] = append(closed[l], c)
+			}
+			if isOpen {
+				open[l] = append(open[l], o)
+			}
+		}
+	}
+	out := make(map[string]LabelDurations, len(labels))
+	for l := range labels {
+		out[l] = LabelDurations{TimeToClose: NewDistribution(closed[l]), OpenAge: NewDistribution(open[l])}
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+func TestNewDistribution(t *testing.T) {
+	tests := []struct {
+		name string
+		days []float64
+		want Distribution
+	}{
+		{"empty", nil, Distribution{}},
+		{"single", []float64{4}, Distribution{Count: 1, Median: 4, P75: 4, P90: 4, P95: 4, Max: 4}},
+		{"even count", []float64{4, 1, 3, 2}, Distribution{Count: 4, Median: 2.5, P75: 3.25, P90: 3.7, P95: 3.85, Max: 4}},
+		{"outlier", []float64{1, 1, 2, 2, 1095}, Distribution{Count: 5, Median: 2, P75: 2, P90: 657.8, P95: 876.4, Max: 1095}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewDistribution(tt.days)
+			round := func(f float64) float64 { return float64(int(f*100+0.5)) / 100 }
+			got.P75, got.P90, got.P95 = round(got.P75), round(got.P90), round(got.P95)
+			if got != tt.want {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestComputePulse_Durations(t *testing.T) {
+	now := time.Now()
+	ago := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	closedAt := func(days int) *time.Time { t := ago(days); return &t }
+	issues := []api.Issue{
+		{Number: 1, State: "closed", Labels: []string{"bug"}, CreatedAt: ago(3), ClosedAt: closedAt(2)},
+		{Number: 2, State: "closed", Labels: []string{"bug"}, CreatedAt: ago(4), ClosedAt: closedAt(2)},
+		{Number: 3, State: "closed", CreatedAt: ago(1100), ClosedAt: closedAt(5)},
+		{Number: 4, State: "open", Labels: []string{"bug", "docs"}, CreatedAt: ago(10)},
+	}
+	pm := ComputePulse(issues)
+	if pm.TimeToClose.Count != 3 || int(pm.TimeToClose.Median+0.5) != 2 || int(pm.TimeToClose.Max+0.5) != 1095 {
+		t.Fatalf("unexpected time to close: %+v", pm.TimeToClose)
+	}
+	if pm.OpenAge.Count != 1 || int(pm.OpenAge.Median+0.5) != 10 {
+		t.Fatalf("unexpected open age: %+v", pm.OpenAge)
+	}
+
+	byLabel := ComputeLabelDurations(issues, now)
+	if byLabel["bug"].TimeToClose.Count != 2 || int(byLabel["bug"].TimeToClose.Median+0.5) != 2 || byLabel["bug"].OpenAge.Count != 1 {
+		t.Fatalf("unexpected bug durations: %+v", byLabel["bug"])
+	}
+	if byLabel["docs"].TimeToClose.Count != 0 || byLabel["docs"].OpenAge.Count != 1 {
+		t.Fatalf("unexpected docs durations: %+v", byLabel["docs"])
+	}
+}
@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+// GroupByFields are the values accepted by GroupPulse.
+var GroupByFields = []string{"label", "assignee", "author", "milestone"}
+
+// PulseGroup holds the pulse metrics of the issues sharing one label,
+// assignee, author or milestone.
+type PulseGroup struct {
+	Key        string
+	Metrics    PulseMetrics
+	OldestOpen *api.Issue `json:",omitempty"` // the open issue created first; nil when none is open
+}
+
+// groupKeys returns the groups an issue belongs to. Issues with several
+// labels or assignees belong to each of them.
+func groupKeys(it api.Issue, by string) []string {
+	switch by {
+	case "label":
+		if len(it.Labels) == 0 {
+			return []string{"unlabeled"}
+		}
+		return it.Labels
+	case "assignee":
+		assignees := it.Assignees
+		if len(assignees) == 0 && it.Assignee != "" {
+			assignees = []string{it.Assignee}
+		}
+		if len(assignees) == 0 {
+			return []string{"unassigned"}
+		}
+		return assignees
+	case "author":
+		if it.Author == "" {
+			return []string{"unknown"}
+		}
+		return []string{it.Author}
+	case "milestone":
+		if it.Milestone == "" {
+			return []string{"no milestone"}
+		}
+		return []string{it.Milestone}
+	}
+	return nil
+}
+
+// GroupPulse computes the pulse metrics of every group of issues, largest
+// group first.
+func GroupPulse(issues []api.Issue, by string) ([]PulseGroup, error) {
+	if !slices.Contains(GroupByFields, by) {
+		return nil, fmt.Errorf("invalid group %q (allowed: label, assignee, author, milestone)", by)
+	}
+
+	members := map[string][]api.Issue{}
+	for _, it := range issues {
+		seen := map[string]bool{}
+		for _, k := range groupKeys(it, by) {
+			if !seen[k] {
+				seen[k] = true
+				members[k] = append(members[k], it)
+			}
+		}
+	}
+
+	var groups []PulseGroup
+	for k, m := range members {
+		g := PulseGroup{Key: k, Metrics: ComputePulse(m)}
+		for i := range m {
+			if m[i].State == "open" && (g.OldestOpen == nil || m[i].CreatedAt.Before(g.OldestOpen.CreatedAt)) {
+				g.OldestOpen = &m[i]
+			}
+		}
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Metrics.Total != groups[j].Metrics.Total {
+			return groups[i].Metrics.Total > groups[j].Metrics.Total
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups, nil
+}
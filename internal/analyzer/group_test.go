@@ -0,0 +1,53 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+func TestGroupPulse(t *testing.T) {
+	now := time.Now()
+	ago := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	closedAt := ago(1)
+	issues := []api.Issue{
+		{Number: 1, State: "open", Labels: []string{"bug", "ui"}, Assignees: []string{"bob"}, CreatedAt: ago(40)},
+		{Number: 2, State: "open", Labels: []string{"bug"}, Assignee: "carol", CreatedAt: ago(50)},
+		{Number: 3, State: "closed", Labels: []string{"bug", "bug"}, CreatedAt: ago(3), ClosedAt: &closedAt},
+		{Number: 4, State: "open", Milestone: "v1", CreatedAt: ago(5)},
+	}
+
+	groups, err := GroupPulse(issues, "label")
+	if err != nil {
+		t.Fatalf("GroupPulse: %v", err)
+	}
+	if len(groups) != 3 || groups[0].Key != "bug" || groups[1].Key != "ui" || groups[2].Key != "unlabeled" {
+		t.Fatalf("unexpected groups: %+v", groups)
+	}
+	bug := groups[0]
+	if bug.Metrics.Total != 3 || bug.Metrics.Open != 2 || bug.Metrics.Closed != 1 || int(bug.Metrics.TimeToClose.Median+0.5) != 2 {
+		t.Fatalf("unexpected bug metrics: %+v", bug.Metrics)
+	}
+	if bug.OldestOpen == nil || bug.OldestOpen.Number != 2 {
+		t.Fatalf("expected #2 as the oldest open bug, got %+v", bug.OldestOpen)
+	}
+
+	groups, _ = GroupPulse(issues, "assignee")
+	keys := map[string]int{}
+	for _, g := range groups {
+		keys[g.Key] = g.Metrics.Total
+	}
+	if keys["bob"] != 1 || keys["carol"] != 1 || keys["unassigned"] != 2 {
+		t.Fatalf("unexpected assignee groups: %v", keys)
+	}
+
+	groups, _ = GroupPulse(issues, "milestone")
+	if len(groups) != 2 || groups[0].Key != "no milestone" || groups[1].Key != "v1" || groups[1].OldestOpen.Number != 4 {
+		t.Fatalf("unexpected milestone groups: %+v", groups)
+	}
+
+	if _, err := GroupPulse(issues, "repo"); err == nil {
+		t.Fatalf("expected an error for an unknown field")
+	}
+}
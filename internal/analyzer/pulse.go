@@ -17,10 +17,17 @@ type PulseMetrics struct {
 	Closed7        int
 	Closed30       int
 	Closed90       int
-	AvgTimeToClose float64 // days
+	AvgTimeToClose float64      // days
+	TimeToClose    Distribution // days, over closed issues
+	OpenAge        Distribution // days since creation, over open issues
 	TopByComments  []api.Issue
 	LabelCounts    map[string]int
-	AssigneeCounts map[string]int
+	AssigneeCounts map[string]int // every assignee of an issue is counted; "unassigned" for none
+	AuthorCounts   map[string]int
+	RepoCounts     map[string]int            // issues per api.Issue.Repo, for selections spanning repositories
+	Trend          *Trend                    `json:",omitempty"` // set by pulse --interval
+	LabelDurations map[string]LabelDurations `json:",omitempty"` // set by pulse --durations-by-label
+	FirstResponse  *ResponseMetrics          `json:",omitempty"` // set by pulse --first-response
 }
 
 // ComputePulse computes basic metrics for the provided issues.
@@ -33,9 +40,12 @@ func ComputePulse(issues []api.Issue) PulseMetrics {
 	var pm PulseMetrics
 	pm.LabelCounts = make(map[string]int)
 	pm.AssigneeCounts = make(map[string]int)
+	pm.AuthorCounts = make(map[string]int)
+	pm.RepoCounts = make(map[string]int)
 
 	var totalCloseDuration time.Duration
 	var closedCountForAvg int
+	var closeSamples, openSamples []float64
 
 	for _, it := range issues {
 		pm.Total++
@@ -72,21 +82,40 @@ func ComputePulse(issues []api.Issue) PulseMetrics {
 				closedCountForAvg++
 			}
 		}
+		if d, ok := closeDays(it); ok {
+			closeSamples = append(closeSamples, d)
+		}
+		if d, ok := openDays(it, now); ok {
+			openSamples = append(openSamples, d)
+		}
 
 		for _, l := range it.Labels {
 			pm.LabelCounts[l]++
 		}
-		assignee := it.Assignee
-		if assignee == "" {
-			assignee = "unassigned"
+		assignees := it.Assignees
+		if len(assignees) == 0 && it.Assignee != "" {
+			assignees = []string{it.Assignee}
+		}
+		if len(assignees) == 0 {
+			pm.AssigneeCounts["unassigned"]++
+		}
+		for _, a := range assignees {
+			pm.AssigneeCounts[a]++
+		}
+		if it.Author != "" {
+			pm.AuthorCounts[it.Author]++
+		}
+		if it.Repo != "" {
+			pm.RepoCounts[it.Repo]++
 		}
-		pm.AssigneeCounts[assignee]++
 	}
 
 	if closedCountForAvg > 0 {
 		avg := totalCloseDuration / time.Duration(closedCountForAvg)
 		pm.AvgTimeToClose = avg.Hours() / 24.0
 	}
+	pm.TimeToClose = NewDistribution(closeSamples)
+	pm.OpenAge = NewDistribution(openSamples)
 
 	// top by comments
 	sorted := make([]api.Issue, len(issues))
@@ -0,0 +1,28 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+func TestComputePulse_AssigneesAndAuthors(t *testing.T) {
+	issues := []api.Issue{
+		{Number: 1, State: "open", Author: "alice", Assignee: "bob", Assignees: []string{"bob", "carol"}},
+		{Number: 2, State: "open", Author: "alice", Assignee: "carol"},
+		{Number: 3, State: "closed", Author: "dave"},
+	}
+	pm := ComputePulse(issues)
+	want := map[string]int{"bob": 1, "carol": 2, "unassigned": 1}
+	for k, v := range want {
+		if pm.AssigneeCounts[k] != v {
+			t.Fatalf("assignee %s: expected %d, got %d (%v)", k, v, pm.AssigneeCounts[k], pm.AssigneeCounts)
+		}
+	}
+	if len(pm.AssigneeCounts) != len(want) {
+		t.Fatalf("unexpected assignees: %v", pm.AssigneeCounts)
+	}
+	if pm.AuthorCounts["alice"] != 2 || pm.AuthorCounts["dave"] != 1 {
+		t.Fatalf("unexpected authors: %v", pm.AuthorCounts)
+	}
+}
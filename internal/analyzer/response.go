@@ -0,0 +1,91 @@
+package analyzer
+
+import (
+	"strings"
+	"time"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+// Activity is one action taken on an issue: a comment or a timeline event.
+type Activity struct {
+	Kind        string // "comment" or the timeline event type (labeled, assigned, closed, ...)
+	Actor       string
+	Association string // author_association of comments; empty for timeline events
+	IsBot       bool
+	At          time.Time
+}
+
+// ResponseOptions selects which activity counts as a response.
+type ResponseOptions struct {
+	ExcludeBots     bool // ignore GitHub Apps and other bot accounts
+	MaintainersOnly bool // only comments by OWNER, MEMBER or COLLABORATOR
+}
+
+// ResponseMetrics describes how quickly issues got a first response.
+type ResponseMetrics struct {
+	TimeToFirstResponse Distribution // days, over the issues with a response
+	Unanswered          int          // issues without a response so far
+}
+
+// responseKinds are the activities that count as a response. Labeling and
+// assigning need triage access to the repository, so they are maintainer
+// actions by definition.
+var responseKinds = map[string]bool{"comment": true, "labeled": true, "assigned": true}
+
+// IsMaintainerAssociation reports whether an author_association belongs to
+// someone with write access to the repository or its organization.
+func IsMaintainerAssociation(a string) bool {
+	switch strings.ToUpper(a) {
+	case "OWNER", "MEMBER", "COLLABORATOR":
+		return true
+	}
+	return false
+}
+
+// FirstResponse returns the time of the first comment, labeling or assignment
+// on it by someone other than its author.
+func FirstResponse(it api.Issue, activity []Activity, opts ResponseOptions) (time.Time, bool) {
+	var first time.Time
+	found := false
+	for _, a := range activity {
+		if !responseKinds[a.Kind] || a.Actor == "" || strings.EqualFold(a.Actor, it.Author) {
+			continue
+		}
+		if opts.ExcludeBots && a.IsBot {
+			continue
+		}
+		if opts.MaintainersOnly && a.Kind == "comment" && !IsMaintainerAssociation(a.Association) {
+			continue
+		}
+		if !found || a.At.Before(first) {
+			first, found = a.At, true
+		}
+	}
+	return first, found
+}
+
+// ComputeFirstResponse computes the time to first response of issues;
+// activity[i] holds the activity of issues[i].
+func ComputeFirstResponse(issues []api.Issue, activity [][]Activity, opts ResponseOptions) ResponseMetrics {
+	var rm ResponseMetrics
+	var days []float64
+	for i, it := range issues {
+		var acts []Activity
+		if i < len(activity) {
+			acts = activity[i]
+		}
+		at, ok := FirstResponse(it, acts, opts)
+		if !ok {
+			rm.Unanswered++
+			continue
+		}
+		d := at.Sub(it.CreatedAt)
+		if d < 0 {
+			d = 0
+		}
+		days = append(days, d.Hours()/24.0)
+	}
+	rm.TimeToFirstResponse = NewDistribution(days)
+	return rm
+}
@@ -0,0 +1,53 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+func TestFirstResponse(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return created.Add(time.Duration(h) * time.Hour) }
+	issue := api.Issue{Number: 1, Author: "alice", CreatedAt: created}
+	activity := []Activity{
+		{Kind: "comment", Actor: "alice", Association: "NONE", At: at(1)},
+		{Kind: "comment", Actor: "github-actions[bot]", Association: "NONE", IsBot: true, At: at(2)},
+		{Kind: "subscribed", Actor: "carol", At: at(3)},
+		{Kind: "comment", Actor: "dave", Association: "CONTRIBUTOR", At: at(4)},
+		{Kind: "labeled", Actor: "erin", At: at(6)},
+		{Kind: "comment", Actor: "bob", Association: "MEMBER", At: at(5)},
+	}
+	tests := []struct {
+		name   string
+		opts   ResponseOptions
+		want   int // hours after creation; -1 for none
+		events []Activity
+	}{
+		{"anyone but the author", ResponseOptions{}, 2, activity},
+		{"no bots", ResponseOptions{ExcludeBots: true}, 4, activity},
+		{"maintainers only", ResponseOptions{ExcludeBots: true, MaintainersOnly: true}, 5, activity},
+		{"labeling counts as maintainer action", ResponseOptions{MaintainersOnly: true}, 6, append([]Activity{}, activity[4])},
+		{"only the author", ResponseOptions{}, -1, activity[:1]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := FirstResponse(issue, tt.events, tt.opts)
+			if tt.want < 0 {
+				if ok {
+					t.Fatalf("expected no response, got %s", got)
+				}
+				return
+			}
+			if !ok || !got.Equal(at(tt.want)) {
+				t.Fatalf("expected a response at +%dh, got %s (%v)", tt.want, got, ok)
+			}
+		})
+	}
+
+	rm := ComputeFirstResponse([]api.Issue{issue, {Number: 2, Author: "bob", CreatedAt: created}}, [][]Activity{activity, nil}, ResponseOptions{ExcludeBots: true})
+	if rm.TimeToFirstResponse.Count != 1 || rm.Unanswered != 1 || rm.TimeToFirstResponse.Median*24 != 4 {
+		t.Fatalf("unexpected metrics: %+v", rm)
+	}
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+// StaleIssue is an open issue without recent activity.
+type StaleIssue struct {
+	Repo   string
+	Number int
+	Title  string
+	Author string
+	Labels []string
+	URL    string
+
+	CreatedAt         time.Time
+	LastActivityAt    time.Time
+	LastActivityType  string // "opened", "comment" or a timeline event type
+	LastActivityActor string
+	DaysStale         int
+
+	// DaysSinceMaintainerReply is nil when no maintainer ever commented.
+	DaysSinceMaintainerReply *int
+	// AuthorSpokeLast is true when the latest comment is the author's, or
+	// nobody commented at all: the issue is waiting on the maintainers.
+	AuthorSpokeLast bool
+}
+
+// StaleOptions tunes which activity keeps an issue fresh.
+type StaleOptions struct {
+	ExcludeBots bool // bot comments and events (e.g. stale bots) do not count as activity
+}
+
+// noiseEvents are timeline events that are side effects of other activity or
+// of notification settings rather than someone working on the issue.
+var noiseEvents = map[string]bool{"subscribed": true, "unsubscribed": true, "mentioned": true}
+
+// ComputeStale returns the open issues whose last activity is at least days
+// old at now, most stale first. activity[i] holds the activity of issues[i].
+func ComputeStale(issues []api.Issue, activity [][]Activity, days int, now time.Time, opts StaleOptions) []StaleIssue {
+	daysSince := func(t time.Time) int {
+		return int(math.Floor(now.Sub(t).Hours() / 24))
+	}
+	var out []StaleIssue
+	for i, it := range issues {
+		if it.State != "open" {
+			continue
+		}
+		var acts []Activity
+		if i < len(activity) {
+			acts = activity[i]
+		}
+		si := StaleIssue{
+			Repo: it.Repo, Number: it.Number, Title: it.Title, Author: it.Author, Labels: it.Labels, URL: it.HTMLURL,
+			CreatedAt:      it.CreatedAt,
+			LastActivityAt: it.CreatedAt, LastActivityType: "opened", LastActivityActor: it.Author,
+			AuthorSpokeLast: true,
+		}
+		var lastComment, lastReply time.Time
+		for _, a := range acts {
+			if a.At.IsZero() || noiseEvents[a.Kind] || (opts.ExcludeBots && a.IsBot) {
+				continue
+			}
+			if a.At.After(si.LastActivityAt) {
+				si.LastActivityAt, si.LastActivityType, si.LastActivityActor = a.At, a.Kind, a.Actor
+			}
+			if a.Kind != "comment" || a.IsBot {
+				continue
+			}
+			author := strings.EqualFold(a.Actor, it.Author)
+			if !a.At.Before(lastComment) {
+				lastComment = a.At
+				si.AuthorSpokeLast = author
+			}
+			if !author && IsMaintainerAssociation(a.Association) && a.At.After(lastReply) {
+				lastReply = a.At
+			}
+		}
+		si.DaysStale = daysSince(si.LastActivityAt)
+		if si.DaysStale < days {
+			continue
+		}
+		if !lastReply.IsZero() {
+			d := daysSince(lastReply)
+			si.DaysSinceMaintainerReply = &d
+		}
+		out = append(out, si)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if !out[i].LastActivityAt.Equal(out[j].LastActivityAt) {
+			return out[i].LastActivityAt.Before(out[j].LastActivityAt)
+		}
+		if out[i].Repo != out[j].Repo {
+			return out[i].Repo < out[j].Repo
+		}
+		return out[i].Number < out[j].Number
+	})
+	return out
+}
@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+func TestComputeStale(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	ago := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	issues := []api.Issue{
+		{Number: 1, State: "open", Author: "alice", CreatedAt: ago(100)},
+		{Number: 2, State: "open", Author: "alice", CreatedAt: ago(90)},
+		{Number: 3, State: "open", Author: "carol", CreatedAt: ago(80)},
+		{Number: 4, State: "closed", Author: "carol", CreatedAt: ago(200)},
+		{Number: 5, State: "open", Author: "dave", CreatedAt: ago(70)},
+	}
+	activity := [][]Activity{
+		// maintainer replied, then the author came back: waiting on us
+		{
+			{Kind: "comment", Actor: "bob", Association: "MEMBER", At: ago(60)},
+			{Kind: "comment", Actor: "alice", Association: "NONE", At: ago(45)},
+			{Kind: "subscribed", Actor: "erin", At: ago(5)},
+		},
+		// recent activity: not stale
+		{{Kind: "labeled", Actor: "bob", At: ago(3)}},
+		// only a stale bot touched it recently
+		{
+			{Kind: "comment", Actor: "bob", Association: "OWNER", At: ago(50)},
+			{Kind: "comment", Actor: "stale[bot]", Association: "NONE", IsBot: true, At: ago(2)},
+		},
+		nil,
+		nil,
+	}
+
+	got := ComputeStale(issues, activity, 30, now, StaleOptions{ExcludeBots: true})
+	if len(got) != 3 || got[0].Number != 5 || got[1].Number != 3 || got[2].Number != 1 {
+		t.Fatalf("expected issues 5, 3 and 1 most stale first, got %+v", got)
+	}
+	if s := got[2]; s.DaysStale != 45 || s.LastActivityType != "comment" || s.LastActivityActor != "alice" || !s.AuthorSpokeLast || s.DaysSinceMaintainerReply == nil || *s.DaysSinceMaintainerReply != 60 {
+		t.Fatalf("unexpected issue 1: %+v", s)
+	}
+	if s := got[1]; s.DaysStale != 50 || s.AuthorSpokeLast || *s.DaysSinceMaintainerReply != 50 {
+		t.Fatalf("unexpected issue 3: %+v", s)
+	}
+	if s := got[0]; s.LastActivityType != "opened" || s.LastActivityActor != "dave" || !s.AuthorSpokeLast || s.DaysSinceMaintainerReply != nil {
+		t.Fatalf("unexpected issue 5: %+v", s)
+	}
+
+	// counting bots, the stale bot comment keeps issue 3 fresh
+	if got := ComputeStale(issues, activity, 30, now, StaleOptions{}); len(got) != 2 {
+		t.Fatalf("expected 2 stale issues counting bots, got %+v", got)
+	}
+}
@@ -0,0 +1,90 @@
+package analyzer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+// TrendBucket holds the activity of one period. Start is inclusive and End is
+// exclusive; the last bucket is the current, unfinished period.
+type TrendBucket struct {
+	Start             time.Time
+	End               time.Time
+	Opened            int
+	Closed            int
+	Net               int     // Opened - Closed
+	Backlog           int     // issues open at End (or now, for the current period)
+	MedianTimeToClose float64 // days, over the issues closed in the period; 0 when none
+}
+
+// Trend is a series of consecutive buckets of one interval.
+type Trend struct {
+	Interval string // week or month
+	Buckets  []TrendBucket
+}
+
+// periodStart returns the start of the week (Monday 00:00 UTC) or month
+// containing t.
+func periodStart(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if interval == "month" {
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+func addPeriods(t time.Time, interval string, n int) time.Time {
+	if interval == "month" {
+		return t.AddDate(0, n, 0)
+	}
+	return t.AddDate(0, 0, 7*n)
+}
+
+// ComputeTrend splits issues into the last periods weeks or months up to and
+// including the one containing now. The backlog of a period counts the
+// issues created before its end and not closed by then, so it only reflects
+// the issues passed in.
+func ComputeTrend(issues []api.Issue, interval string, periods int, now time.Time) (Trend, error) {
+	if interval != "week" && interval != "month" {
+		return Trend{}, fmt.Errorf("invalid interval %q (allowed: week, month)", interval)
+	}
+	if periods <= 0 {
+		return Trend{}, fmt.Errorf("periods must be positive, got %d", periods)
+	}
+
+	tr := Trend{Interval: interval}
+	current := periodStart(now, interval)
+	for i := periods - 1; i >= 0; i-- {
+		start := addPeriods(current, interval, -i)
+		end := addPeriods(start, interval, 1)
+		b := TrendBucket{Start: start, End: end}
+
+		// the current period ends now as far as the backlog is concerned
+		cut := end
+		if now.Before(cut) {
+			cut = now
+		}
+		var closed []float64
+		for _, it := range issues {
+			if !it.CreatedAt.Before(start) && it.CreatedAt.Before(end) {
+				b.Opened++
+			}
+			if it.ClosedAt != nil && !it.ClosedAt.Before(start) && it.ClosedAt.Before(end) {
+				b.Closed++
+				if d, ok := closeDays(it); ok {
+					closed = append(closed, d)
+				}
+			}
+			if it.CreatedAt.Before(cut) && (it.ClosedAt == nil || !it.ClosedAt.Before(cut)) {
+				b.Backlog++
+			}
+		}
+		b.Net = b.Opened - b.Closed
+		b.MedianTimeToClose = NewDistribution(closed).Median
+		tr.Buckets = append(tr.Buckets, b)
+	}
+	return tr, nil
+}
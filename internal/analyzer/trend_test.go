@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+func TestComputeTrend_Weeks(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 12, 0, 0, 0, time.UTC) }
+	closed := func(d int) *time.Time { t := day(d); return &t }
+	// 2025-03-03 and 2025-03-10 are Mondays; now is Wednesday 2025-03-12
+	issues := []api.Issue{
+		{Number: 1, CreatedAt: day(1), ClosedAt: closed(4)},  // before the window, closed in week 1
+		{Number: 2, CreatedAt: day(3), ClosedAt: closed(5)},  // opened and closed in week 1
+		{Number: 3, CreatedAt: day(4)},                       // still open
+		{Number: 4, CreatedAt: day(6), ClosedAt: closed(11)}, // closed in the current week
+		{Number: 5, CreatedAt: day(11)},
+	}
+	tr, err := ComputeTrend(issues, "week", 2, day(12))
+	if err != nil {
+		t.Fatalf("ComputeTrend: %v", err)
+	}
+	if len(tr.Buckets) != 2 || !tr.Buckets[0].Start.Equal(time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected buckets: %+v", tr.Buckets)
+	}
+	w1, w2 := tr.Buckets[0], tr.Buckets[1]
+	if w1.Opened != 3 || w1.Closed != 2 || w1.Net != 1 || w1.Backlog != 2 || w1.MedianTimeToClose != 2.5 {
+		t.Fatalf("unexpected first week: %+v", w1)
+	}
+	if w2.Opened != 1 || w2.Closed != 1 || w2.Net != 0 || w2.Backlog != 2 || w2.MedianTimeToClose != 5 {
+		t.Fatalf("unexpected current week: %+v", w2)
+	}
+
+	if _, err := ComputeTrend(issues, "day", 2, day(12)); err == nil {
+		t.Fatalf("expected an error for an unknown interval")
+	}
+}
+
+func TestComputeTrend_Months(t *testing.T) {
+	now := time.Date(2025, 3, 31, 23, 0, 0, 0, time.UTC)
+	tr, err := ComputeTrend(nil, "month", 3, now)
+	if err != nil {
+		t.Fatalf("ComputeTrend: %v", err)
+	}
+	want := []string{"2025-01-01", "2025-02-01", "2025-03-01"}
+	for i, b := range tr.Buckets {
+		if b.Start.Format("2006-01-02") != want[i] {
+			t.Fatalf("bucket %d starts %s, want %s", i, b.Start, want[i])
+		}
+	}
+	if !tr.Buckets[2].End.Equal(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected end of the last bucket: %s", tr.Buckets[2].End)
+	}
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	ghapi "github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+// cacheEntry is the on-disk representation of a cached response.
+type cacheEntry struct {
+	Key      string          `json:"key"`
+	ETag     string          `json:"etag,omitempty"`
+	StoredAt time.Time       `json:"stored_at"`
+	Body     json.RawMessage `json:"body"`
+}
+
+// cacheClient implements RESTClient (and graphQLDoer) on top of an on-disk
+// response cache. REST responses are revalidated with If-None-Match once they
+// are older than ttl; a 304 reuses the stored body and does not count against
+// the rate limit. GraphQL responses have no ETag to revalidate with, so they
+// are only cached when ttl is set and are reused within it.
+type cacheClient struct {
+	http    *http.Client
+	baseURL string
+	gql     graphQLDoer
+	dir     string
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+// newCacheClient returns a cacheClient storing entries under dir.
+func newCacheClient(httpClient *http.Client, baseURL string, gql graphQLDoer, dir string, ttl time.Duration) (*cacheClient, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create cache directory: %w", err)
+	}
+	return &cacheClient{http: httpClient, baseURL: baseURL, gql: gql, dir: dir, ttl: ttl, now: time.Now}, nil
+}
+
+func (c *cacheClient) Get(path string, out interface{}) error {
+	return c.GetContext(context.Background(), path, out)
+}
+
+func (c *cacheClient) GetContext(ctx context.Context, path string, out interface{}) error {
+	key := "GET " + c.baseURL + path
+	entry, ok := c.load(key)
+	if ok && c.fresh(entry) {
+		return json.Unmarshal(entry.Body, out)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	if ok && entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && ok {
+		entry.StoredAt = c.now()
+		c.save(entry)
+		return json.Unmarshal(entry.Body, out)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return ghapi.HandleHTTPError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return err
+	}
+	c.save(cacheEntry{Key: key, ETag: resp.Header.Get("ETag"), StoredAt: c.now(), Body: body})
+	return nil
+}
+
+// DoWithContext runs a GraphQL query, reusing a stored response that is younger
+// than ttl. With a ttl of 0 the cache is bypassed, since a stored response
+// could not be revalidated.
+func (c *cacheClient) DoWithContext(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	if c.ttl <= 0 {
+		return c.gql.DoWithContext(ctx, query, variables, response)
+	}
+	vars, err := json.Marshal(variables)
+	if err != nil {
+		return err
+	}
+	key := "POST " + c.baseURL + "graphql " + query + " " + string(vars)
+	if entry, ok := c.load(key); ok && c.now().Sub(entry.StoredAt) < c.ttl {
+		return json.Unmarshal(entry.Body, response)
+	}
+
+	var raw json.RawMessage
+	if err := c.gql.DoWithContext(ctx, query, variables, &raw); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, response); err != nil {
+		return err
+	}
+	c.save(cacheEntry{Key: key, StoredAt: c.now(), Body: raw})
+	return nil
+}
+
+func (c *cacheClient) fresh(e cacheEntry) bool {
+	return c.ttl > 0 && c.now().Sub(e.StoredAt) < c.ttl
+}
+
+func (c *cacheClient) file(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *cacheClient) load(key string) (cacheEntry, bool) {
+	var e cacheEntry
+	b, err := os.ReadFile(c.file(key))
+	if err != nil {
+		return e, false
+	}
+	if err := json.Unmarshal(b, &e); err != nil || e.Key != key {
+		return e, false
+	}
+	return e, true
+}
+
+// save writes an entry atomically. Cache write failures are not fatal: the
+// response has already been returned to the caller.
+func (c *cacheClient) save(e cacheEntry) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(c.dir, "entry-*.tmp")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(b)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.file(e.Key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// DefaultCacheDir returns the directory used for cached responses when
+// Settings.CacheDir is empty.
+func DefaultCacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "gh-issue-miner"), nil
+}
+
+// restBaseURL mirrors go-gh's REST URL prefix for a host.
+func restBaseURL(host string) string {
+	h := auth.NormalizeHostname(host)
+	switch {
+	case auth.IsEnterprise(h):
+		return fmt.Sprintf("https://%s/api/v3/", h)
+	case strings.EqualFold(h, "github.localhost"):
+		return fmt.Sprintf("http://api.%s/", h)
+	default:
+		return fmt.Sprintf("https://api.%s/", h)
+	}
+}
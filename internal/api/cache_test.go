@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCacheClient_RevalidatesWithETag(t *testing.T) {
+	var requests, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"number":1,"title":"cached"}`)
+	}))
+	defer srv.Close()
+
+	c, err := newCacheClient(srv.Client(), srv.URL+"/", nil, t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("newCacheClient: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		var out map[string]interface{}
+		if err := c.Get("repos/o/r/issues/1", &out); err != nil {
+			t.Fatalf("Get #%d: %v", i, err)
+		}
+		if out["title"] != "cached" {
+			t.Fatalf("Get #%d returned %v", i, out)
+		}
+	}
+	if requests != 2 || notModified != 1 {
+		t.Fatalf("expected one full and one conditional request, got requests=%d notModified=%d", requests, notModified)
+	}
+}
+
+func TestCacheClient_TTLSkipsRequests(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `[]`)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c, err := newCacheClient(srv.Client(), srv.URL+"/", nil, t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("newCacheClient: %v", err)
+	}
+	c.now = func() time.Time { return now }
+
+	var out []interface{}
+	for i := 0; i < 3; i++ {
+		if err := c.Get("repos/o/r/labels", &out); err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+	}
+	if requests != 1 {
+		t.Fatalf("expected 1 request within ttl, got %d", requests)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if err := c.Get("repos/o/r/labels", &out); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected expired entry to be refetched, got %d requests", requests)
+	}
+}
+
+func TestCacheClient_ErrorsAreNotCached(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c, err := newCacheClient(srv.Client(), srv.URL+"/", nil, t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatalf("newCacheClient: %v", err)
+	}
+	var out interface{}
+	for i := 0; i < 2; i++ {
+		if err := c.Get("repos/o/r/issues/404", &out); err == nil {
+			t.Fatalf("expected error for 404")
+		}
+	}
+	if requests != 2 {
+		t.Fatalf("expected errors to bypass the cache, got %d requests", requests)
+	}
+}
+
+// countingGraphQL answers every query with the same result and counts the queries.
+type countingGraphQL struct{ queries int }
+
+func (g *countingGraphQL) DoWithContext(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	g.queries++
+	return json.Unmarshal([]byte(`{"repository":{"name":"r"}}`), response)
+}
+
+func TestCacheClient_GraphQLTTL(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	vars := map[string]interface{}{"owner": "o", "name": "r"}
+	run := func(c *cacheClient, times int) {
+		t.Helper()
+		for i := 0; i < times; i++ {
+			var out map[string]interface{}
+			if err := c.DoWithContext(context.Background(), "query{repository{name}}", vars, &out); err != nil {
+				t.Fatalf("DoWithContext: %v", err)
+			}
+			if out["repository"] == nil {
+				t.Fatalf("unexpected response %v", out)
+			}
+		}
+	}
+
+	// ttl 0, the --cache-ttl default, cannot revalidate, so it bypasses the cache
+	dir := t.TempDir()
+	gql := &countingGraphQL{}
+	c, err := newCacheClient(http.DefaultClient, "https://api.example.com/", gql, dir, 0)
+	if err != nil {
+		t.Fatalf("newCacheClient: %v", err)
+	}
+	c.now = func() time.Time { return now }
+	run(c, 2)
+	if gql.queries != 2 {
+		t.Fatalf("expected every query to reach the API without a ttl, got %d", gql.queries)
+	}
+
+	gql = &countingGraphQL{}
+	if c, err = newCacheClient(http.DefaultClient, "https://api.example.com/", gql, dir, time.Minute); err != nil {
+		t.Fatalf("newCacheClient: %v", err)
+	}
+	c.now = func() time.Time { return now }
+	run(c, 2)
+	if gql.queries != 1 {
+		t.Fatalf("expected 1 query within the ttl, got %d", gql.queries)
+	}
+	now = now.Add(time.Minute)
+	run(c, 1)
+	if gql.queries != 2 {
+		t.Fatalf("expected an expired response to be refetched, got %d queries", gql.queries)
+	}
+}
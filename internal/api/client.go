@@ -1,51 +1,339 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	ghapi "github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
 )
 
 // RESTClient defines the subset of methods we use from go-gh's REST client.
+// GetContext honors cancellation; Get is equivalent to GetContext with context.Background().
 type RESTClient interface {
 	Get(path string, out interface{}) error
+	GetContext(ctx context.Context, path string, out interface{}) error
 }
 
-// retryClient wraps a RESTClient and retries transient failures with exponential backoff.
+// GraphQLClient is implemented by clients that can also run GraphQL queries.
+// Listing functions type-assert for it and fall back to REST when it is absent.
+type GraphQLClient interface {
+	GraphQL(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error
+}
+
+// graphQLDoer is the subset of go-gh's GraphQL client we use.
+type graphQLDoer interface {
+	DoWithContext(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error
+}
+
+// ghRESTClient adapts go-gh's REST client to RESTClient.
+type ghRESTClient struct {
+	c *ghapi.RESTClient
+}
+
+func (g ghRESTClient) Get(path string, out interface{}) error {
+	return g.c.Get(path, out)
+}
+
+func (g ghRESTClient) GetContext(ctx context.Context, path string, out interface{}) error {
+	return g.c.DoWithContext(ctx, http.MethodGet, path, nil, out)
+}
+
+// retryClient wraps a RESTClient, pauses for primary and secondary rate limits,
+// and retries transient failures with exponential backoff.
 type retryClient struct {
-	inner     RESTClient
-	attempts  int
-	baseDelay time.Duration
+	inner RESTClient
+	gql   graphQLDoer
+	// gqlHeaders records the headers of GraphQL responses; nil when unknown.
+	gqlHeaders *headerRecorder
+	attempts   int
+	baseDelay  time.Duration
+	// maxWaits bounds how many rate-limit pauses a single call may take.
+	maxWaits int
+
+	// sleep, now and log default to sleepContext, time.Now and os.Stderr; tests replace them.
+	sleep func(ctx context.Context, d time.Duration) error
+	now   func() time.Time
+	log   io.Writer
 }
 
 func (r *retryClient) Get(path string, out interface{}) error {
+	return r.GetContext(context.Background(), path, out)
+}
+
+func (r *retryClient) GetContext(ctx context.Context, path string, out interface{}) error {
+	return r.retry(ctx, func() error { return r.inner.GetContext(ctx, path, out) })
+}
+
+// GraphQL runs a GraphQL query with the same retry policy as Get.
+func (r *retryClient) GraphQL(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	return r.retry(ctx, func() error {
+		err := r.gql.DoWithContext(ctx, query, variables, out)
+		if err != nil && r.gqlHeaders != nil {
+			if h := r.gqlHeaders.headers(); h != nil {
+				err = &graphQLHeaderError{err: err, headers: h}
+			}
+		}
+		return err
+	})
+}
+
+// sleepContext pauses for d or until ctx is cancelled.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func (r *retryClient) retry(ctx context.Context, call func() error) error {
+	sleep, now, log := r.sleep, r.now, r.log
+	if sleep == nil {
+		sleep = sleepContext
+	}
+	if now == nil {
+		now = time.Now
+	}
+	if log == nil {
+		log = os.Stderr
+	}
+
 	var last error
 	delay := r.baseDelay
-	for i := 0; i < r.attempts; i++ {
-		last = r.inner.Get(path, out)
+	waits := 0
+	for attempt := 0; attempt < r.attempts; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		last = call()
 		if last == nil {
 			return nil
 		}
-		// conservative non-retriable detection: 404 / 401
-		s := last.Error()
-		if strings.Contains(s, "404") || strings.Contains(s, "Not Found") || strings.Contains(s, "401") || strings.Contains(s, "Unauthorized") {
+		if ctx.Err() != nil {
 			return last
 		}
-		time.Sleep(delay)
-		delay *= 2
+		// rate limits pause until the window resets and do not use up an attempt
+		if rl, ok := rateLimitFromError(last, now()); ok {
+			if waits >= r.maxWaits || rl.Wait > maxRateLimitWait {
+				return fmt.Errorf("GitHub API %s rate limit exceeded: %w", rl.Kind, last)
+			}
+			waits++
+			fmt.Fprintf(log, "warning: %s; waiting %s before retrying\n", rl.describe(), rl.Wait.Round(time.Second))
+			if err := sleep(ctx, rl.Wait); err != nil {
+				return err
+			}
+			continue
+		}
+		if !isRetriable(last) {
+			return last
+		}
+		attempt++
+		if attempt < r.attempts {
+			if err := sleep(ctx, delay); err != nil {
+				return err
+			}
+			delay *= 2
+		}
 	}
 	return last
 }
 
-// NewRESTClient returns the default go-gh REST client wrapped with retry/backoff.
+// ClientSettings configures the clients built by NewRESTClient.
+type ClientSettings struct {
+	// Cache enables the on-disk response cache.
+	Cache bool
+	// CacheDir overrides the cache location (default: DefaultCacheDir).
+	CacheDir string
+	// CacheTTL is how long cached responses are reused without contacting the API.
+	// Older entries are revalidated with their ETag; 0 always revalidates.
+	CacheTTL time.Duration
+	// BaseURL, when set, sends REST requests to this URL (for example a local
+	// test server) over plain net/http without GitHub authentication. GraphQL is
+	// not used in that mode.
+	BaseURL string
+	// Host is the default GitHub host (for example a GitHub Enterprise Server
+	// hostname). Empty means the gh default: GH_HOST or the configured host.
+	Host string
+}
+
+// Settings is read by NewRESTClient. Commands populate it from global flags.
+var Settings ClientSettings
+
+// DefaultHost returns the host used for repositories that do not name one:
+// Settings.Host, or gh's default host.
+func DefaultHost() string {
+	if Settings.Host != "" {
+		return auth.NormalizeHostname(strings.ToLower(Settings.Host))
+	}
+	host, _ := auth.DefaultHost()
+	return auth.NormalizeHostname(strings.ToLower(host))
+}
+
+// IsDefaultHost reports whether host is empty or names the default host.
+func IsDefaultHost(host string) bool {
+	return host == "" || auth.NormalizeHostname(strings.ToLower(host)) == DefaultHost()
+}
+
+// QualifyRepo returns repo (owner/repo) prefixed with its host when the host
+// is not the default one, as in HOST/OWNER/REPO. Repositories on different
+// hosts therefore never share a name.
+func QualifyRepo(host, repo string) string {
+	if IsDefaultHost(host) {
+		return repo
+	}
+	return auth.NormalizeHostname(strings.ToLower(host)) + "/" + repo
+}
+
+// requests counts the HTTP requests sent to the API by every client of the
+// process; see Requests.
+var requests atomic.Int64
+
+// Requests returns the number of HTTP requests sent to the API so far by the
+// clients of NewRESTClientForHost: listings, searches, GraphQL queries and
+// retries all count, cache revalidations too, while responses served from
+// the on-disk cache without a request, --offline and --replay do not.
+func Requests() int64 {
+	return requests.Load()
+}
+
+// countingTransport counts the requests it sends in requests. It sits below
+// the cache and retry layers, so it sees what goes over the network.
+type countingTransport struct {
+	inner http.RoundTripper
+}
+
+func (t countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requests.Add(1)
+	return t.inner.RoundTrip(req)
+}
+
+// NewRESTClient returns the client for the default host; see NewRESTClientForHost.
 func NewRESTClient() (RESTClient, error) {
-	c, err := ghapi.DefaultRESTClient()
+	return NewRESTClientForHost(DefaultHost())
+}
+
+// NewRESTClientForHost returns the go-gh REST client for host wrapped with
+// retry/backoff, and with the on-disk cache when Settings.Cache is set.
+// The returned client also implements GraphQLClient.
+func NewRESTClientForHost(host string) (RESTClient, error) {
+	if Settings.BaseURL != "" {
+		return newBaseURLClient(Settings.BaseURL)
+	}
+	transport := countingTransport{inner: http.DefaultTransport}
+	opts := ghapi.ClientOptions{Host: host, Transport: transport}
+	c, err := ghapi.NewRESTClient(opts)
 	if err != nil {
 		return nil, err
 	}
-	return &retryClient{inner: c, attempts: 3, baseDelay: 500 * time.Millisecond}, nil
+	// the GraphQL client records rate limit headers, which its errors lack
+	recorder := &headerRecorder{inner: transport}
+	g, err := ghapi.NewGraphQLClient(ghapi.ClientOptions{Host: host, Transport: recorder})
+	if err != nil {
+		return nil, err
+	}
+	var inner RESTClient = ghRESTClient{c: c}
+	var gql graphQLDoer = g
+	if Settings.Cache {
+		dir := Settings.CacheDir
+		if dir == "" {
+			if dir, err = DefaultCacheDir(); err != nil {
+				return nil, err
+			}
+		}
+		httpClient, err := ghapi.NewHTTPClient(opts)
+		if err != nil {
+			return nil, err
+		}
+		cc, err := newCacheClient(httpClient, restBaseURL(host), g, dir, Settings.CacheTTL)
+		if err != nil {
+			return nil, err
+		}
+		inner, gql = cc, cc
+	}
+	return &retryClient{inner: inner, gql: gql, gqlHeaders: recorder, attempts: 3, baseDelay: 500 * time.Millisecond, maxWaits: 5}, nil
+}
+
+// httpRESTClient is a minimal REST client for Settings.BaseURL.
+type httpRESTClient struct {
+	http    *http.Client
+	baseURL string
+}
+
+func (h httpRESTClient) Get(path string, out interface{}) error {
+	return h.GetContext(context.Background(), path, out)
+}
+
+func (h httpRESTClient) GetContext(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := h.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return ghapi.HandleHTTPError(resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// restOnly hides the GraphQL method of the wrapped client so listing uses REST.
+type restOnly struct {
+	RESTClient
+}
+
+// newBaseURLClient builds the client used when Settings.BaseURL is set: the
+// same cache and retry layers as the default client, over httpRESTClient.
+func newBaseURLClient(baseURL string) (RESTClient, error) {
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	httpClient := &http.Client{Transport: countingTransport{inner: http.DefaultTransport}}
+	var inner RESTClient = httpRESTClient{http: httpClient, baseURL: baseURL}
+	if Settings.Cache {
+		dir := Settings.CacheDir
+		if dir == "" {
+			var err error
+			if dir, err = DefaultCacheDir(); err != nil {
+				return nil, err
+			}
+		}
+		cc, err := newCacheClient(httpClient, baseURL, nil, dir, Settings.CacheTTL)
+		if err != nil {
+			return nil, err
+		}
+		inner = cc
+	}
+	return restOnly{&retryClient{inner: inner, attempts: 3, baseDelay: 500 * time.Millisecond, maxWaits: 5}}, nil
 }
 
 // NewClient is a variable wrapper around NewRESTClient so tests can override it.
 var NewClient = NewRESTClient
+
+// NewHostClient builds the client for a host other than the default one.
+// Like NewClient it is a variable so tests and --offline/--replay can override it.
+var NewHostClient = NewRESTClientForHost
+
+// ClientForHost returns NewClient() for the default host and NewHostClient(host)
+// for any other host, so requests for a GitHub Enterprise Server repository are
+// never sent to github.com or the other way around.
+func ClientForHost(host string) (RESTClient, error) {
+	if IsDefaultHost(host) {
+		return NewClient()
+	}
+	return NewHostClient(auth.NormalizeHostname(strings.ToLower(host)))
+}
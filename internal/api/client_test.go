@@ -0,0 +1,44 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequests_CountsNetworkRequests(t *testing.T) {
+	failures := 1
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/o/r/issues/2" && failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"number":1}`)
+	}))
+	defer srv.Close()
+
+	orig := Settings
+	defer func() { Settings = orig }()
+	Settings = ClientSettings{BaseURL: srv.URL, Cache: true, CacheDir: t.TempDir(), CacheTTL: time.Hour}
+	c, err := NewRESTClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := Requests()
+	var out map[string]interface{}
+	// the second read of issue 1 is served from the cache
+	for _, path := range []string{"repos/o/r/issues/1", "repos/o/r/issues/1", "repos/o/r/issues/2"} {
+		if err := c.Get(path, &out); err != nil {
+			t.Fatalf("Get %s: %v", path, err)
+		}
+	}
+	// one request for issue 1 and two for issue 2, which failed once
+	if got := Requests() - before; got != 3 {
+		t.Fatalf("Requests() grew by %d, want 3", got)
+	}
+}
@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -13,6 +12,18 @@ type Comment struct {
 	Author    string
 	Body      string
 	CreatedAt time.Time
+
+	AuthorAssociation string // OWNER, MEMBER, COLLABORATOR, CONTRIBUTOR, NONE, ...
+	AuthorIsBot       bool
+}
+
+// restComment is the REST issue comment object.
+type restComment struct {
+	ID                int64     `json:"id"`
+	Body              string    `json:"body"`
+	User              *restUser `json:"user"`
+	CreatedAt         time.Time `json:"created_at"`
+	AuthorAssociation string    `json:"author_association"`
 }
 
 // ListIssueComments fetches comments for an issue (paginated).
@@ -21,39 +32,21 @@ func ListIssueComments(ctx context.Context, client RESTClient, repo string, numb
 	page := 1
 	perPage := 100
 	for {
-		path := fmt.Sprintf("repos/%s/issues/%d/comments?per_page=%d&page=%d", repo, number, perPage, page)
-		var raw interface{}
-		if err := client.Get(path, &raw); err != nil {
-			return nil, err
-		}
-		body, err := json.Marshal(raw)
-		if err != nil {
+		if err := ctx.Err(); err != nil {
 			return nil, err
 		}
-		var items []map[string]interface{}
-		if err := json.Unmarshal(body, &items); err != nil {
+		path := fmt.Sprintf("repos/%s/issues/%d/comments?per_page=%d&page=%d", repo, number, perPage, page)
+		var items []restComment
+		if err := getJSON(ctx, client, path, &items); err != nil {
 			return nil, err
 		}
 		if len(items) == 0 {
 			break
 		}
 		for _, it := range items {
-			var c Comment
-			if id, ok := it["id"].(float64); ok {
-				c.ID = int64(id)
-			}
-			if b, ok := it["body"].(string); ok {
-				c.Body = b
-			}
-			if a, ok := it["user"].(map[string]interface{}); ok {
-				if login, ok := a["login"].(string); ok {
-					c.Author = login
-				}
-			}
-			if created, ok := it["created_at"].(string); ok {
-				if tm, err := time.Parse(time.RFC3339, created); err == nil {
-					c.CreatedAt = tm
-				}
+			c := Comment{ID: it.ID, Body: it.Body, CreatedAt: it.CreatedAt, AuthorAssociation: it.AuthorAssociation, AuthorIsBot: it.User.isBot()}
+			if it.User != nil {
+				c.Author = it.User.Login
 			}
 			out = append(out, c)
 		}
@@ -0,0 +1,349 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// issuesQuery lists issues with their labels, assignees, author, comments and
+// cross-reference timeline items in a single paginated request.
+const issuesQuery = `
+query ListIssues($owner: String!, $name: String!, $first: Int!, $after: String, $states: [IssueState!], $filterBy: IssueFilters, $orderBy: IssueOrder) {
+  repository(owner: $owner, name: $name) {
+    issues(first: $first, after: $after, states: $states, filterBy: $filterBy, orderBy: $orderBy) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        number
+        state
+        title
+        body
+        createdAt
+        updatedAt
+        closedAt
+        author { login }
+        authorAssociation
+        url
+        stateReason
+        locked
+        milestone { title }
+        reactionGroups { content reactors { totalCount } }
+        labels(first: 100) { nodes { name } }
+        assignees(first: 100) { nodes { login } }
+        comments(first: 100) {
+          totalCount
+          pageInfo { hasNextPage }
+          nodes { databaseId body createdAt author { login __typename } authorAssociation }
+        }
+        timelineItems(first: 100, itemTypes: [CROSS_REFERENCED_EVENT]) {
+          pageInfo { hasNextPage }
+          nodes {
+            ... on CrossReferencedEvent {
+              createdAt
+              actor { login }
+              source {
+                ... on Issue { number repository { nameWithOwner } }
+                ... on PullRequest { number repository { nameWithOwner } }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// graphQLIssuesPageSize is kept below the REST page size because every issue
+// node also carries up to 100 comments and 100 timeline items.
+const graphQLIssuesPageSize = 50
+
+type gqlLogin struct {
+	Login    string `json:"login"`
+	Typename string `json:"__typename"`
+}
+
+type gqlIssue struct {
+	Number    int        `json:"number"`
+	State     string     `json:"state"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	ClosedAt  *time.Time `json:"closedAt"`
+	Author    *gqlLogin  `json:"author"`
+
+	AuthorAssociation string `json:"authorAssociation"`
+	URL               string `json:"url"`
+	StateReason       string `json:"stateReason"`
+	Locked            bool   `json:"locked"`
+	Milestone         *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	ReactionGroups []struct {
+		Content  string `json:"content"`
+		Reactors struct {
+			TotalCount int `json:"totalCount"`
+		} `json:"reactors"`
+	} `json:"reactionGroups"`
+
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Assignees struct {
+		Nodes []gqlLogin `json:"nodes"`
+	} `json:"assignees"`
+	Comments struct {
+		TotalCount int `json:"totalCount"`
+		PageInfo   struct {
+			HasNextPage bool `json:"hasNextPage"`
+		} `json:"pageInfo"`
+		Nodes []struct {
+			DatabaseID        int64     `json:"databaseId"`
+			Body              string    `json:"body"`
+			CreatedAt         time.Time `json:"createdAt"`
+			Author            *gqlLogin `json:"author"`
+			AuthorAssociation string    `json:"authorAssociation"`
+		} `json:"nodes"`
+	} `json:"comments"`
+	TimelineItems struct {
+		PageInfo struct {
+			HasNextPage bool `json:"hasNextPage"`
+		} `json:"pageInfo"`
+		Nodes []struct {
+			CreatedAt time.Time `json:"createdAt"`
+			Actor     *gqlLogin `json:"actor"`
+			Source    *struct {
+				Number     int `json:"number"`
+				Repository struct {
+					NameWithOwner string `json:"nameWithOwner"`
+				} `json:"repository"`
+			} `json:"source"`
+		} `json:"nodes"`
+	} `json:"timelineItems"`
+}
+
+type gqlIssuesResponse struct {
+	Repository *struct {
+		Issues struct {
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []gqlIssue `json:"nodes"`
+		} `json:"issues"`
+	} `json:"repository"`
+}
+
+// ListIssuesGraphQL lists issues using the GraphQL API and has the same signature
+// as ListIssues so it can be installed as ListIssuesFunc. Comments and
+// cross-reference timeline events are returned inline on each Issue.
+// It falls back to the REST ListIssues when the client cannot run GraphQL
+// queries or when pull requests are requested (the issues connection excludes them).
+// Like the REST listing, issues must carry all labels: filterBy.labels matches
+// any of them, so only the first is sent and the rest are checked here.
+func ListIssuesGraphQL(ctx context.Context, client RESTClient, repo string, limit int, state string, labels []string, includePRs bool, assignee string, author string, sort string, direction string, since *time.Time) ([]Issue, error) {
+	gql, ok := client.(GraphQLClient)
+	if !ok || includePRs {
+		return ListIssues(ctx, client, repo, limit, state, labels, includePRs, assignee, author, sort, direction, since)
+	}
+
+	owner, name, found := strings.Cut(repo, "/")
+	if !found || owner == "" || name == "" {
+		return nil, fmt.Errorf("invalid repository %q: expected owner/repo", repo)
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+
+	vars := map[string]interface{}{
+		"owner": owner,
+		"name":  name,
+	}
+	switch strings.ToLower(state) {
+	case "open":
+		vars["states"] = []string{"OPEN"}
+	case "closed":
+		vars["states"] = []string{"CLOSED"}
+	}
+	filterBy := map[string]interface{}{}
+	var required []string
+	if len(labels) > 0 {
+		filterBy["labels"] = labels[:1]
+		required = labels[1:]
+	}
+	if assignee != "" {
+		filterBy["assignee"] = assignee
+	}
+	if author != "" {
+		filterBy["createdBy"] = author
+	}
+	if since != nil {
+		filterBy["since"] = since.UTC().Format(time.RFC3339)
+	}
+	if len(filterBy) > 0 {
+		vars["filterBy"] = filterBy
+	}
+	// match the REST defaults: created, descending
+	order := map[string]interface{}{"field": "CREATED_AT", "direction": "DESC"}
+	switch sort {
+	case "updated":
+		order["field"] = "UPDATED_AT"
+	case "comments":
+		order["field"] = "COMMENTS"
+	}
+	if strings.EqualFold(direction, "asc") {
+		order["direction"] = "ASC"
+	}
+	vars["orderBy"] = order
+
+	var result []Issue
+	var cursor string
+	for len(result) < limit {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		first := limit - len(result)
+		if first > graphQLIssuesPageSize || len(required) > 0 {
+			first = graphQLIssuesPageSize
+		}
+		vars["first"] = first
+		if cursor != "" {
+			vars["after"] = cursor
+		}
+
+		var resp gqlIssuesResponse
+		if err := gql.GraphQL(ctx, issuesQuery, vars, &resp); err != nil {
+			return nil, err
+		}
+		if resp.Repository == nil {
+			return nil, fmt.Errorf("repository %s not found", repo)
+		}
+		conn := resp.Repository.Issues
+		for _, n := range conn.Nodes {
+			if len(result) >= limit {
+				break
+			}
+			iss := issueFromGraphQL(n)
+			if !hasAllLabels(iss.Labels, required) {
+				continue
+			}
+			result = append(result, iss)
+		}
+		if !conn.PageInfo.HasNextPage || conn.PageInfo.EndCursor == "" {
+			break
+		}
+		cursor = conn.PageInfo.EndCursor
+	}
+	return result, nil
+}
+
+// hasAllLabels reports whether have contains every label of want. Label
+// names are compared case-insensitively, as GitHub does.
+func hasAllLabels(have, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if strings.EqualFold(h, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// issueFromGraphQL converts a GraphQL issue node into an Issue. Comments and
+// timeline events are only attached when they were fetched completely.
+func issueFromGraphQL(n gqlIssue) Issue {
+	iss := Issue{
+		Number:    n.Number,
+		State:     strings.ToLower(n.State),
+		Title:     n.Title,
+		Body:      n.Body,
+		CreatedAt: n.CreatedAt,
+		UpdatedAt: n.UpdatedAt,
+		ClosedAt:  n.ClosedAt,
+		Comments:  n.Comments.TotalCount,
+
+		AuthorAssociation: n.AuthorAssociation,
+		HTMLURL:           n.URL,
+		// GraphQL enums (NOT_PLANNED) are reported in the REST spelling (not_planned)
+		StateReason: strings.ToLower(n.StateReason),
+		Locked:      n.Locked,
+	}
+	for _, l := range n.Labels.Nodes {
+		iss.Labels = append(iss.Labels, l.Name)
+	}
+	for _, a := range n.Assignees.Nodes {
+		iss.Assignees = append(iss.Assignees, a.Login)
+	}
+	if len(iss.Assignees) > 0 {
+		iss.Assignee = iss.Assignees[0]
+	}
+	if n.Author != nil {
+		iss.Author = n.Author.Login
+	}
+	if n.Milestone != nil {
+		iss.Milestone = n.Milestone.Title
+	}
+	for _, g := range n.ReactionGroups {
+		c := g.Reactors.TotalCount
+		iss.Reactions.Total += c
+		switch g.Content {
+		case "THUMBS_UP":
+			iss.Reactions.ThumbsUp = c
+		case "THUMBS_DOWN":
+			iss.Reactions.ThumbsDown = c
+		case "LAUGH":
+			iss.Reactions.Laugh = c
+		case "HOORAY":
+			iss.Reactions.Hooray = c
+		case "CONFUSED":
+			iss.Reactions.Confused = c
+		case "HEART":
+			iss.Reactions.Heart = c
+		case "ROCKET":
+			iss.Reactions.Rocket = c
+		case "EYES":
+			iss.Reactions.Eyes = c
+		}
+	}
+
+	if !n.Comments.PageInfo.HasNextPage {
+		iss.CommentList = []Comment{}
+		for _, c := range n.Comments.Nodes {
+			cm := Comment{ID: c.DatabaseID, Body: c.Body, CreatedAt: c.CreatedAt, AuthorAssociation: c.AuthorAssociation}
+			if c.Author != nil {
+				cm.Author = c.Author.Login
+				cm.AuthorIsBot = c.Author.Typename == "Bot" || strings.HasSuffix(c.Author.Login, "[bot]")
+			}
+			iss.CommentList = append(iss.CommentList, cm)
+		}
+	}
+
+	if !n.TimelineItems.PageInfo.HasNextPage {
+		iss.TimelineEvents = []TimelineEvent{}
+		for _, ev := range n.TimelineItems.Nodes {
+			if ev.Source == nil || ev.Source.Number == 0 {
+				continue
+			}
+			te := TimelineEvent{
+				Type:              "cross-referenced",
+				CreatedAt:         ev.CreatedAt,
+				SourceOwnerRepo:   ev.Source.Repository.NameWithOwner,
+				SourceIssueNumber: ev.Source.Number,
+			}
+			if ev.Actor != nil {
+				te.Actor = ev.Actor.Login
+			}
+			iss.TimelineEvents = append(iss.TimelineEvents, te)
+		}
+	}
+	return iss
+}
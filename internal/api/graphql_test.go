@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeGraphQLClient serves canned GraphQL pages keyed by the "after" cursor.
+type fakeGraphQLClient struct {
+	pages map[string]string
+	vars  []map[string]interface{}
+	gets  []string
+}
+
+func (f *fakeGraphQLClient) Get(path string, out interface{}) error {
+	f.gets = append(f.gets, path)
+	return json.Unmarshal([]byte(`[]`), out)
+}
+
+func (f *fakeGraphQLClient) GetContext(ctx context.Context, path string, out interface{}) error {
+	return f.Get(path, out)
+}
+
+func (f *fakeGraphQLClient) GraphQL(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	cp := map[string]interface{}{}
+	for k, v := range variables {
+		cp[k] = v
+	}
+	f.vars = append(f.vars, cp)
+	after, _ := variables["after"].(string)
+	page, ok := f.pages[after]
+	if !ok {
+		return errors.New("unexpected cursor " + after)
+	}
+	return json.Unmarshal([]byte(page), out)
+}
+
+const gqlPage1 = `{"repository":{"issues":{
+  "pageInfo":{"hasNextPage":true,"endCursor":"c1"},
+  "nodes":[{
+    "number":1,"state":"OPEN","title":"first","body":"see #2",
+    "createdAt":"2025-01-01T00:00:00Z","updatedAt":"2025-01-02T00:00:00Z","closedAt":null,
+    "author":{"login":"octocat"},"authorAssociation":"MEMBER","url":"https://github.com/owner/repo/issues/1",
+    "stateReason":null,"locked":false,"milestone":{"title":"v2"},
+    "reactionGroups":[{"content":"THUMBS_UP","reactors":{"totalCount":4}},{"content":"HEART","reactors":{"totalCount":1}}],
+    "labels":{"nodes":[{"name":"bug"}]},
+    "assignees":{"nodes":[{"login":"alice"},{"login":"bob"}]},
+    "comments":{"totalCount":1,"pageInfo":{"hasNextPage":false},
+      "nodes":[{"databaseId":11,"body":"ref #3","createdAt":"2025-01-01T01:00:00Z","author":{"login":"carol"}}]},
+    "timelineItems":{"pageInfo":{"hasNextPage":false},
+      "nodes":[{"createdAt":"2025-01-03T00:00:00Z","actor":{"login":"dave"},
+        "source":{"number":7,"repository":{"nameWithOwner":"other/repo"}}},{}]}
+  }]}}}`
+
+const gqlPage2 = `{"repository":{"issues":{
+  "pageInfo":{"hasNextPage":false,"endCursor":"c2"},
+  "nodes":[{
+    "number":2,"state":"CLOSED","stateReason":"NOT_PLANNED","title":"second","body":"",
+    "createdAt":"2025-01-01T00:00:00Z","updatedAt":"2025-01-05T00:00:00Z","closedAt":"2025-01-05T00:00:00Z",
+    "labels":{"nodes":[]},"assignees":{"nodes":[]},
+    "comments":{"totalCount":150,"pageInfo":{"hasNextPage":true},"nodes":[]},
+    "timelineItems":{"pageInfo":{"hasNextPage":true},"nodes":[]}
+  }]}}}`
+
+func TestListIssuesGraphQL_PaginatesAndMaps(t *testing.T) {
+	f := &fakeGraphQLClient{pages: map[string]string{"": gqlPage1, "c1": gqlPage2}}
+	issues, err := ListIssuesGraphQL(context.Background(), f, "owner/repo", 10, "all", []string{"bug"}, false, "alice", "", "updated", "asc", nil)
+	if err != nil {
+		t.Fatalf("ListIssuesGraphQL returned error: %v", err)
+	}
+	if len(f.gets) != 0 {
+		t.Fatalf("expected no REST calls, got %v", f.gets)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues))
+	}
+
+	first := issues[0]
+	if first.State != "open" || first.Assignee != "alice" || first.Comments != 1 {
+		t.Fatalf("unexpected first issue: %+v", first)
+	}
+	if first.Author != "octocat" || strings.Join(first.Assignees, ",") != "alice,bob" || first.Milestone != "v2" ||
+		first.AuthorAssociation != "MEMBER" || first.Reactions.Total != 5 || first.Reactions.ThumbsUp != 4 || first.Reactions.Heart != 1 {
+		t.Fatalf("unexpected first issue metadata: %+v", first)
+	}
+	if len(first.CommentList) != 1 || first.CommentList[0].ID != 11 || first.CommentList[0].Author != "carol" {
+		t.Fatalf("unexpected prefetched comments: %+v", first.CommentList)
+	}
+	if len(first.TimelineEvents) != 1 {
+		t.Fatalf("expected 1 timeline event, got %+v", first.TimelineEvents)
+	}
+	ev := first.TimelineEvents[0]
+	if ev.SourceOwnerRepo != "other/repo" || ev.SourceIssueNumber != 7 || ev.Actor != "dave" || ev.Type != "cross-referenced" {
+		t.Fatalf("unexpected timeline event: %+v", ev)
+	}
+
+	second := issues[1]
+	if second.ClosedAt == nil || second.State != "closed" || second.StateReason != "not_planned" {
+		t.Fatalf("unexpected second issue: %+v", second)
+	}
+	if second.CommentList != nil || second.TimelineEvents != nil {
+		t.Fatalf("truncated connections must not be attached: %+v", second)
+	}
+
+	if len(f.vars) != 2 || f.vars[1]["after"] != "c1" {
+		t.Fatalf("expected second request with cursor c1, got %v", f.vars)
+	}
+	if _, ok := f.vars[0]["states"]; ok {
+		t.Fatalf("state all must not send states: %v", f.vars[0])
+	}
+	order := f.vars[0]["orderBy"].(map[string]interface{})
+	if order["field"] != "UPDATED_AT" || order["direction"] != "ASC" {
+		t.Fatalf("unexpected orderBy: %v", order)
+	}
+	filterBy := f.vars[0]["filterBy"].(map[string]interface{})
+	if filterBy["assignee"] != "alice" || strings.Join(filterBy["labels"].([]string), ",") != "bug" {
+		t.Fatalf("unexpected filterBy: %v", filterBy)
+	}
+}
+
+func TestListIssuesGraphQL_RequiresAllLabels(t *testing.T) {
+	// the fake ignores filterBy, so only the client-side check applies
+	f := &fakeGraphQLClient{pages: map[string]string{"": gqlPage1, "c1": gqlPage2}}
+	issues, err := ListIssuesGraphQL(context.Background(), f, "owner/repo", 1, "all", []string{"docs", "BUG"}, false, "", "", "", "", nil)
+	if err != nil {
+		t.Fatalf("ListIssuesGraphQL returned error: %v", err)
+	}
+	// filterBy.labels matches any label, so only the first is sent, with full pages
+	filterBy := f.vars[0]["filterBy"].(map[string]interface{})
+	if strings.Join(filterBy["labels"].([]string), ",") != "docs" || f.vars[0]["first"] != graphQLIssuesPageSize {
+		t.Fatalf("unexpected request: %v", f.vars[0])
+	}
+	if len(issues) != 1 || issues[0].Number != 1 {
+		t.Fatalf("expected only issue 1, which is labeled bug, got %+v", issues)
+	}
+
+	// no issue carries docs; every page is read looking for one
+	f = &fakeGraphQLClient{pages: map[string]string{"": gqlPage1, "c1": gqlPage2}}
+	issues, err = ListIssuesGraphQL(context.Background(), f, "owner/repo", 10, "all", []string{"bug", "docs"}, false, "", "", "", "", nil)
+	if err != nil || len(issues) != 0 || len(f.vars) != 2 {
+		t.Fatalf("expected no issues after two pages, got %+v (%v) after %d requests", issues, err, len(f.vars))
+	}
+}
+
+func TestListIssuesGraphQL_FallsBackToREST(t *testing.T) {
+	f := &fakeGraphQLClient{}
+	if _, err := ListIssuesGraphQL(context.Background(), f, "owner/repo", 10, "", nil, true, "", "", "", "", nil); err != nil {
+		t.Fatalf("ListIssuesGraphQL returned error: %v", err)
+	}
+	if len(f.vars) != 0 || len(f.gets) != 1 || !strings.HasPrefix(f.gets[0], "repos/owner/repo/issues?") {
+		t.Fatalf("expected a single REST list call, got gql=%v rest=%v", f.vars, f.gets)
+	}
+}
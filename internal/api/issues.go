@@ -10,19 +10,52 @@ import (
 	"time"
 )
 
-// Issue is a minimal issue representation used for output and analysis.
+// Issue is the issue representation used for output and analysis.
 type Issue struct {
 	Number    int
 	State     string
 	Title     string
 	Body      string
 	Labels    []string
-	Assignee  string
+	Assignee  string // first assignee, kept for compatibility; see Assignees
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	ClosedAt  *time.Time
 	Comments  int
 	IsPR      bool
+
+	// Repo is the repository the issue belongs to (owner/repo, or
+	// HOST/OWNER/REPO off the default host). The commands set it, so issues
+	// selected from several repositories can be told apart.
+	Repo string
+
+	Author            string
+	Assignees         []string
+	Milestone         string // milestone title
+	StateReason       string // completed, not_planned or reopened; empty when unknown
+	Locked            bool
+	AuthorAssociation string // OWNER, MEMBER, COLLABORATOR, CONTRIBUTOR, NONE, ...
+	HTMLURL           string
+	Reactions         Reactions
+
+	// CommentList and TimelineEvents hold comments and cross-reference
+	// timeline events when the listing backend returned them inline.
+	// nil means they were not prefetched and must be fetched separately.
+	CommentList    []Comment       `json:"-"`
+	TimelineEvents []TimelineEvent `json:"-"`
+}
+
+// Reactions counts the reactions on an issue by type.
+type Reactions struct {
+	Total      int
+	ThumbsUp   int
+	ThumbsDown int
+	Laugh      int
+	Hooray     int
+	Confused   int
+	Heart      int
+	Rocket     int
+	Eyes       int
 }
 
 // ListIssues lists issues for the given repo (owner/repo) up to limit.
@@ -38,6 +71,9 @@ func ListIssues(ctx context.Context, client RESTClient, repo string, limit int,
 	page := 1
 
 	for len(result) < limit {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		// build path
 		qs := url.Values{}
 		if state == "" {
@@ -68,18 +104,8 @@ func ListIssues(ctx context.Context, client RESTClient, repo string, limit int,
 		qs.Set("page", strconv.Itoa(page))
 		path := fmt.Sprintf("repos/%s/issues?%s", repo, qs.Encode())
 
-		// Use client.Get into an interface{}, then marshal/unmarshal to work with dynamic JSON
-		var raw interface{}
-		if err := client.Get(path, &raw); err != nil {
-			return nil, err
-		}
-		body, err := json.Marshal(raw)
-		if err != nil {
-			return nil, err
-		}
-
-		var items []map[string]interface{}
-		if err := json.Unmarshal(body, &items); err != nil {
+		var items []restIssue
+		if err := getJSON(ctx, client, path, &items); err != nil {
 			return nil, err
 		}
 
@@ -91,57 +117,7 @@ func ListIssues(ctx context.Context, client RESTClient, repo string, limit int,
 			if len(result) >= limit {
 				break
 			}
-			var iss Issue
-			if n, ok := it["number"].(float64); ok {
-				iss.Number = int(n)
-			}
-			if s, ok := it["state"].(string); ok {
-				iss.State = s
-			}
-			if t, ok := it["title"].(string); ok {
-				iss.Title = t
-			}
-			if b, ok := it["body"].(string); ok {
-				iss.Body = b
-			}
-			if comments, ok := it["comments"].(float64); ok {
-				iss.Comments = int(comments)
-			}
-			// detect pull request via presence of pull_request field
-			if _, ok := it["pull_request"].(map[string]interface{}); ok {
-				iss.IsPR = true
-			}
-			if created, ok := it["created_at"].(string); ok {
-				if tm, err := time.Parse(time.RFC3339, created); err == nil {
-					iss.CreatedAt = tm
-				}
-			}
-			if updated, ok := it["updated_at"].(string); ok {
-				if tm, err := time.Parse(time.RFC3339, updated); err == nil {
-					iss.UpdatedAt = tm
-				}
-			}
-			if closed, ok := it["closed_at"].(string); ok && closed != "" {
-				if tm, err := time.Parse(time.RFC3339, closed); err == nil {
-					iss.ClosedAt = &tm
-				}
-			}
-			// labels
-			if lbls, ok := it["labels"].([]interface{}); ok {
-				for _, l := range lbls {
-					if lm, ok := l.(map[string]interface{}); ok {
-						if name, ok := lm["name"].(string); ok {
-							iss.Labels = append(iss.Labels, name)
-						}
-					}
-				}
-			}
-			// assignee
-			if asg, ok := it["assignee"].(map[string]interface{}); ok && asg != nil {
-				if login, ok := asg["login"].(string); ok {
-					iss.Assignee = login
-				}
-			}
+			iss := it.toIssue()
 
 			// If PRs should be excluded, skip PRs
 			if !includePRs && iss.IsPR {
@@ -163,73 +139,139 @@ func ListIssues(ctx context.Context, client RESTClient, repo string, limit int,
 	return result, nil
 }
 
-// GetIssue fetches a single issue by number from the given repo (owner/repo).
-func GetIssue(ctx context.Context, client RESTClient, repo string, number int) (Issue, error) {
-	var iss Issue
-	path := fmt.Sprintf("repos/%s/issues/%d", repo, number)
-	var raw interface{}
-	if err := client.Get(path, &raw); err != nil {
-		return iss, err
-	}
-	body, err := json.Marshal(raw)
-	if err != nil {
-		return iss, err
-	}
-	var m map[string]interface{}
-	if err := json.Unmarshal(body, &m); err != nil {
-		return iss, err
+// restUser is the user object embedded in REST responses.
+type restUser struct {
+	Login string `json:"login"`
+	Type  string `json:"type"` // User, Bot or Organization
+}
+
+// isBot reports whether the user is a GitHub App or other bot account. The
+// type is not always present (for example in older fixtures), so the "[bot]"
+// login suffix GitHub gives app accounts counts as well.
+func (u *restUser) isBot() bool {
+	return u != nil && (u.Type == "Bot" || strings.HasSuffix(u.Login, "[bot]"))
+}
+
+// restLabel is a label object as returned by the issues and labels endpoints.
+type restLabel struct {
+	Name string `json:"name"`
+}
+
+// restIssue is the REST issue object shared by the list, single-issue and
+// search endpoints. Timestamps are decoded strictly: a malformed value fails
+// the request instead of leaving a zero time behind.
+type restIssue struct {
+	Number      int             `json:"number"`
+	State       string          `json:"state"`
+	Title       string          `json:"title"`
+	Body        string          `json:"body"`
+	Comments    int             `json:"comments"`
+	Labels      []restLabel     `json:"labels"`
+	Assignee    *restUser       `json:"assignee"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	ClosedAt    *time.Time      `json:"closed_at"`
+	PullRequest json.RawMessage `json:"pull_request"`
+
+	User      *restUser  `json:"user"`
+	Assignees []restUser `json:"assignees"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	StateReason       string `json:"state_reason"`
+	Locked            bool   `json:"locked"`
+	AuthorAssociation string `json:"author_association"`
+	HTMLURL           string `json:"html_url"`
+	Reactions         struct {
+		TotalCount int `json:"total_count"`
+		ThumbsUp   int `json:"+1"`
+		ThumbsDown int `json:"-1"`
+		Laugh      int `json:"laugh"`
+		Hooray     int `json:"hooray"`
+		Confused   int `json:"confused"`
+		Heart      int `json:"heart"`
+		Rocket     int `json:"rocket"`
+		Eyes       int `json:"eyes"`
+	} `json:"reactions"`
+}
+
+func (r restIssue) toIssue() Issue {
+	iss := Issue{
+		Number:    r.Number,
+		State:     r.State,
+		Title:     r.Title,
+		Body:      r.Body,
+		Comments:  r.Comments,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+		ClosedAt:  r.ClosedAt,
+		// pull requests carry a pull_request object; issues omit it or send null
+		IsPR:              len(r.PullRequest) > 0 && string(r.PullRequest) != "null",
+		StateReason:       r.StateReason,
+		Locked:            r.Locked,
+		AuthorAssociation: r.AuthorAssociation,
+		HTMLURL:           r.HTMLURL,
+		Reactions: Reactions{
+			Total:      r.Reactions.TotalCount,
+			ThumbsUp:   r.Reactions.ThumbsUp,
+			ThumbsDown: r.Reactions.ThumbsDown,
+			Laugh:      r.Reactions.Laugh,
+			Hooray:     r.Reactions.Hooray,
+			Confused:   r.Reactions.Confused,
+			Heart:      r.Reactions.Heart,
+			Rocket:     r.Reactions.Rocket,
+			Eyes:       r.Reactions.Eyes,
+		},
 	}
-	if n, ok := m["number"].(float64); ok {
-		iss.Number = int(n)
+	for _, l := range r.Labels {
+		iss.Labels = append(iss.Labels, l.Name)
 	}
-	if s, ok := m["state"].(string); ok {
-		iss.State = s
+	for _, a := range r.Assignees {
+		iss.Assignees = append(iss.Assignees, a.Login)
 	}
-	if t, ok := m["title"].(string); ok {
-		iss.Title = t
+	if r.Assignee != nil {
+		iss.Assignee = r.Assignee.Login
 	}
-	if b, ok := m["body"].(string); ok {
-		iss.Body = b
+	// older payloads (and some fakes) only carry the singular assignee
+	if len(iss.Assignees) == 0 && iss.Assignee != "" {
+		iss.Assignees = []string{iss.Assignee}
 	}
-	if comments, ok := m["comments"].(float64); ok {
-		iss.Comments = int(comments)
+	if iss.Assignee == "" && len(iss.Assignees) > 0 {
+		iss.Assignee = iss.Assignees[0]
 	}
-	if _, ok := m["pull_request"].(map[string]interface{}); ok {
-		iss.IsPR = true
+	if r.User != nil {
+		iss.Author = r.User.Login
 	}
-	if created, ok := m["created_at"].(string); ok {
-		if tm, err := time.Parse(time.RFC3339, created); err == nil {
-			iss.CreatedAt = tm
-		}
+	if r.Milestone != nil {
+		iss.Milestone = r.Milestone.Title
 	}
-	if updated, ok := m["updated_at"].(string); ok {
-		if tm, err := time.Parse(time.RFC3339, updated); err == nil {
-			iss.UpdatedAt = tm
-		}
-	}
-	if closed, ok := m["closed_at"].(string); ok && closed != "" {
-		if tm, err := time.Parse(time.RFC3339, closed); err == nil {
-			iss.ClosedAt = &tm
-		}
-	}
-	if lbls, ok := m["labels"].([]interface{}); ok {
-		for _, l := range lbls {
-			if lm, ok := l.(map[string]interface{}); ok {
-				if name, ok := lm["name"].(string); ok {
-					iss.Labels = append(iss.Labels, name)
-				}
-			}
-		}
+	return iss
+}
+
+// getJSON fetches path and decodes the response into out. Decoding happens
+// here rather than in the client so malformed data is reported with the path
+// and is not retried like a transport error.
+func getJSON(ctx context.Context, client RESTClient, path string, out interface{}) error {
+	var raw json.RawMessage
+	if err := client.GetContext(ctx, path, &raw); err != nil {
+		return err
 	}
-	if asg, ok := m["assignee"].(map[string]interface{}); ok && asg != nil {
-		if login, ok := asg["login"].(string); ok {
-			iss.Assignee = login
-		}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("decode response of %s: %w", path, err)
 	}
+	return nil
+}
 
-	return iss, nil
+// GetIssue fetches a single issue by number from the given repo (owner/repo).
+func GetIssue(ctx context.Context, client RESTClient, repo string, number int) (Issue, error) {
+	var it restIssue
+	if err := getJSON(ctx, client, fmt.Sprintf("repos/%s/issues/%d", repo, number), &it); err != nil {
+		return Issue{}, err
+	}
+	return it.toIssue(), nil
 }
 
-// ListIssuesFunc is a package-level variable pointing to the ListIssues implementation.
+// ListIssuesFunc is a package-level variable pointing to the issue listing implementation.
+// It defaults to ListIssuesGraphQL, which falls back to the REST ListIssues when needed.
 // Tests can replace this with a mock function to exercise higher-level calling code.
-var ListIssuesFunc = ListIssues
+var ListIssuesFunc = ListIssuesGraphQL
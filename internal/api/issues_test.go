@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+const restPR = `{"number":2,"state":"closed","title":"pr","comments":0,"labels":[],"assignee":null,
+  "pull_request":{"url":"https://api.github.com/repos/o/r/pulls/2"},
+  "created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-03T00:00:00Z","closed_at":"2025-01-03T00:00:00Z"}`
+
+const restIssuesPage = `[
+ {"number":1,"state":"open","title":"bug","body":null,"comments":2,
+  "labels":[{"name":"bug"},{"name":"p1"}],"assignee":{"login":"alice"},
+  "assignees":[{"login":"alice"},{"login":"bob"}],"user":{"login":"carol"},"author_association":"CONTRIBUTOR",
+  "milestone":{"title":"v1.0"},"locked":true,"state_reason":null,"html_url":"https://github.com/o/r/issues/1",
+  "reactions":{"total_count":3,"+1":2,"eyes":1},
+  "created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-02T00:00:00Z","closed_at":null},
+ ` + restPR + `
+]`
+
+func TestListIssues_DecodesTypedFields(t *testing.T) {
+	c := cannedClient{"repos/o/r/issues?page=1&per_page=100&state=all": restIssuesPage}
+	issues, err := ListIssues(context.Background(), c, "o/r", 10, "", nil, true, "", "", "", "", nil)
+	if err != nil {
+		t.Fatalf("ListIssues: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues))
+	}
+	first, second := issues[0], issues[1]
+	if first.Assignee != "alice" || strings.Join(first.Labels, ",") != "bug,p1" || first.Comments != 2 || first.IsPR || first.ClosedAt != nil {
+		t.Fatalf("unexpected first issue: %+v", first)
+	}
+	if first.Author != "carol" || strings.Join(first.Assignees, ",") != "alice,bob" || first.Milestone != "v1.0" || !first.Locked ||
+		first.AuthorAssociation != "CONTRIBUTOR" || first.HTMLURL != "https://github.com/o/r/issues/1" ||
+		first.Reactions.Total != 3 || first.Reactions.ThumbsUp != 2 || first.Reactions.Eyes != 1 {
+		t.Fatalf("unexpected metadata: %+v", first)
+	}
+	if !first.CreatedAt.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected created_at: %s", first.CreatedAt)
+	}
+	if !second.IsPR || second.ClosedAt == nil || second.Assignee != "" {
+		t.Fatalf("unexpected second issue: %+v", second)
+	}
+
+	// the list and single-issue paths share the decoder
+	c["repos/o/r/issues/2"] = restPR
+	single, err := GetIssue(context.Background(), c, "o/r", 2)
+	if err != nil || single.Number != 2 || !single.IsPR || !single.ClosedAt.Equal(*second.ClosedAt) {
+		t.Fatalf("GetIssue: %+v (%v)", single, err)
+	}
+}
+
+func TestDecodeErrorsAreReported(t *testing.T) {
+	ctx := context.Background()
+	c := cannedClient{
+		"repos/o/r/issues/1":                              `{"number":1,"created_at":"yesterday"}`,
+		"repos/o/r/issues/1/comments?per_page=100&page=1": `[{"id":1,"created_at":"2025-02-30T00:00:00Z"}]`,
+	}
+	if _, err := GetIssue(ctx, c, "o/r", 1); err == nil || !strings.Contains(err.Error(), "repos/o/r/issues/1") {
+		t.Fatalf("expected a decode error naming the path, got %v", err)
+	}
+	if _, err := ListIssueComments(ctx, c, "o/r", 1); err == nil {
+		t.Fatalf("expected an error for an invalid comment timestamp")
+	}
+}
+
+func TestGetIssueTimeline_DecodesCrossReferences(t *testing.T) {
+	c := cannedClient{"repos/o/r/issues/5/timeline?per_page=100&page=1": `[
+	 {"event":"committed","sha":"abc"},
+	 {"id":7,"event":"cross-referenced","actor":{"login":"bob"},"created_at":"2025-01-04T00:00:00Z",
+	  "source":{"type":"issue","issue":{"number":3,"url":"https://api.github.com/repos/other/repo/issues/3"}}},
+	 {"id":8,"event":"labeled","actor":{"login":"bob"},"created_at":"2025-01-04T00:00:00Z"}
+	]`}
+	evs, err := GetIssueTimeline(context.Background(), c, "o/r", 5)
+	if err != nil {
+		t.Fatalf("GetIssueTimeline: %v", err)
+	}
+	if len(evs) != 1 || evs[0].SourceOwnerRepo != "other/repo" || evs[0].SourceIssueNumber != 3 || evs[0].Actor != "bob" || evs[0].ID != 7 {
+		t.Fatalf("unexpected events: %+v", evs)
+	}
+}
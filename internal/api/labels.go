@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -13,33 +12,28 @@ func ListRepoLabels(ctx context.Context, client RESTClient, repo string) ([]stri
 	page := 1
 	perPage := 100
 	for {
-		path := fmt.Sprintf("repos/%s/labels?per_page=%d&page=%d", repo, perPage, page)
-		var raw interface{}
-		if err := client.Get(path, &raw); err != nil {
-			return nil, err
-		}
-		body, err := json.Marshal(raw)
-		if err != nil {
+		if err := ctx.Err(); err != nil {
 			return nil, err
 		}
-		var items []map[string]interface{}
-		if err := json.Unmarshal(body, &items); err != nil {
+		path := fmt.Sprintf("repos/%s/labels?per_page=%d&page=%d", repo, perPage, page)
+		var items []restLabel
+		if err := getJSON(ctx, client, path, &items); err != nil {
 			return nil, err
 		}
 		if len(items) == 0 {
 			break
 		}
 		for _, it := range items {
-			if n, ok := it["name"].(string); ok {
-				out = append(out, n)
-			}
+			out = append(out, it.Name)
 		}
 		if len(items) < perPage {
 			break
 		}
 		page++
 		// small throttle in case callers loop rapidly
-		time.Sleep(10 * time.Millisecond)
+		if err := sleepContext(ctx, 10*time.Millisecond); err != nil {
+			return nil, err
+		}
 	}
 	return out, nil
 }
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	ghapi "github.com/cli/go-gh/v2/pkg/api"
+)
+
+// defaultRateLimitWait is used when a rate limit response says nothing about when
+// to retry (no Retry-After or reset header); GitHub asks clients to wait at least a minute.
+const defaultRateLimitWait = time.Minute
+
+// maxRateLimitWait bounds a single pause. The primary limit resets hourly, so
+// anything longer indicates a bad header and is reported instead of waited out.
+const maxRateLimitWait = 65 * time.Minute
+
+// rateLimit describes a rate-limited response and how long to pause before retrying.
+type rateLimit struct {
+	Kind      string // "primary" or "secondary"
+	Wait      time.Duration
+	Reset     time.Time // zero when unknown
+	Limit     string    // X-RateLimit-Limit, when present
+	Remaining string    // X-RateLimit-Remaining, when present
+}
+
+// describe renders the rate limit for the stderr wait message.
+func (rl rateLimit) describe() string {
+	msg := "GitHub API " + rl.Kind + " rate limit exceeded"
+	if rl.Limit != "" && rl.Remaining != "" {
+		msg += fmt.Sprintf(" (%s of %s requests remaining)", rl.Remaining, rl.Limit)
+	}
+	if !rl.Reset.IsZero() {
+		msg += fmt.Sprintf(", resets at %s", rl.Reset.Local().Format("15:04:05"))
+	}
+	return msg
+}
+
+// headerRecorder is the transport of the GraphQL client. It keeps the rate
+// limit headers of the last response, because go-gh returns GraphQL errors
+// without them. The limit is per account, so the latest response of any
+// concurrent query tells when the window resets.
+type headerRecorder struct {
+	inner http.RoundTripper
+	mu    sync.Mutex
+	last  http.Header
+}
+
+func (h *headerRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := h.inner.RoundTrip(req)
+	if err == nil && resp.Header.Get("X-RateLimit-Reset") != "" {
+		h.mu.Lock()
+		h.last = resp.Header.Clone()
+		h.mu.Unlock()
+	}
+	return resp, err
+}
+
+// headers returns the rate limit headers of the last response, or nil.
+func (h *headerRecorder) headers() http.Header {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.last
+}
+
+// graphQLHeaderError attaches the rate limit headers seen by the GraphQL
+// client to a GraphQL error.
+type graphQLHeaderError struct {
+	err     error
+	headers http.Header
+}
+
+func (e *graphQLHeaderError) Error() string { return e.err.Error() }
+func (e *graphQLHeaderError) Unwrap() error { return e.err }
+
+// rateLimitFromError inspects an API error for primary and secondary rate limits
+// using the X-RateLimit-Remaining/Reset and Retry-After headers. A GraphQL
+// RATE_LIMITED error waits for X-RateLimit-Reset when the headers of the
+// response are attached (see graphQLHeaderError).
+func rateLimitFromError(err error, now time.Time) (rateLimit, bool) {
+	var gqlErr *ghapi.GraphQLError
+	if errors.As(err, &gqlErr) {
+		for _, e := range gqlErr.Errors {
+			if e.Type == "RATE_LIMITED" {
+				rl := rateLimit{Kind: "primary", Wait: defaultRateLimitWait}
+				var hErr *graphQLHeaderError
+				if errors.As(err, &hErr) {
+					rl.Limit, rl.Remaining = hErr.headers.Get("X-RateLimit-Limit"), hErr.headers.Get("X-RateLimit-Remaining")
+					if secs, err := strconv.ParseInt(hErr.headers.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+						rl.Reset = time.Unix(secs, 0)
+						rl.Wait = rl.Reset.Sub(now) + time.Second
+						if rl.Wait <= 0 {
+							rl.Wait = time.Second
+						}
+					}
+				}
+				return rl, true
+			}
+		}
+		return rateLimit{}, false
+	}
+
+	var httpErr *ghapi.HTTPError
+	if !errors.As(err, &httpErr) {
+		return rateLimit{}, false
+	}
+	if httpErr.StatusCode != http.StatusForbidden && httpErr.StatusCode != http.StatusTooManyRequests {
+		return rateLimit{}, false
+	}
+	h := httpErr.Headers
+	rl := rateLimit{Limit: h.Get("X-RateLimit-Limit"), Remaining: h.Get("X-RateLimit-Remaining")}
+
+	if ra := h.Get("Retry-After"); ra != "" {
+		rl.Kind = "secondary"
+		if secs, err := strconv.Atoi(ra); err == nil {
+			rl.Wait = time.Duration(secs) * time.Second
+		} else if at, err := http.ParseTime(ra); err == nil {
+			rl.Wait = at.Sub(now)
+		}
+		if rl.Wait <= 0 {
+			rl.Wait = time.Second
+		}
+		return rl, true
+	}
+
+	if rl.Remaining == "0" {
+		rl.Kind = "primary"
+		if secs, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			rl.Reset = time.Unix(secs, 0)
+			// add a second of slack so the window has actually rolled over
+			rl.Wait = rl.Reset.Sub(now) + time.Second
+		}
+		if rl.Wait <= 0 {
+			rl.Wait = time.Second
+		}
+		return rl, true
+	}
+
+	if strings.Contains(strings.ToLower(httpErr.Message), "secondary rate limit") {
+		rl.Kind = "secondary"
+		rl.Wait = defaultRateLimitWait
+		return rl, true
+	}
+	return rateLimit{}, false
+}
+
+// isRetriable reports whether a non-rate-limit error may succeed on retry:
+// 5xx responses, network and timeout errors, and connections dropped mid
+// response are retried. Other API errors and local failures (such as a body
+// that does not decode) are permanent.
+func isRetriable(err error) bool {
+	var httpErr *ghapi.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+}
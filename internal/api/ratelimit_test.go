@@ -0,0 +1,209 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	ghapi "github.com/cli/go-gh/v2/pkg/api"
+)
+
+// scriptedClient returns the queued errors in order, then succeeds.
+type scriptedClient struct {
+	errs  []error
+	calls int
+}
+
+func (s *scriptedClient) Get(path string, out interface{}) error {
+	s.calls++
+	if len(s.errs) == 0 {
+		return nil
+	}
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+	return err
+}
+
+func (s *scriptedClient) GetContext(ctx context.Context, path string, out interface{}) error {
+	return s.Get(path, out)
+}
+
+func httpErr(status int, headers map[string]string, msg string) error {
+	h := http.Header{}
+	for k, v := range headers {
+		h.Set(k, v)
+	}
+	return &ghapi.HTTPError{StatusCode: status, Headers: h, Message: msg}
+}
+
+func newTestRetryClient(inner RESTClient, now time.Time) (*retryClient, *[]time.Duration, *bytes.Buffer) {
+	var slept []time.Duration
+	var log bytes.Buffer
+	return &retryClient{
+		inner:     inner,
+		attempts:  3,
+		baseDelay: 10 * time.Millisecond,
+		maxWaits:  2,
+		sleep: func(ctx context.Context, d time.Duration) error {
+			slept = append(slept, d)
+			return nil
+		},
+		now: func() time.Time { return now },
+		log: &log,
+	}, &slept, &log
+}
+
+func TestRetryClient_WaitsForPrimaryReset(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	reset := now.Add(90 * time.Second)
+	inner := &scriptedClient{errs: []error{httpErr(403, map[string]string{
+		"X-RateLimit-Limit":     "5000",
+		"X-RateLimit-Remaining": "0",
+		"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+	}, "API rate limit exceeded")}}
+	c, slept, log := newTestRetryClient(inner, now)
+
+	if err := c.Get("repos/o/r/issues", nil); err != nil {
+		t.Fatalf("expected success after waiting, got %v", err)
+	}
+	if len(*slept) != 1 || (*slept)[0] != 91*time.Second {
+		t.Fatalf("expected one 91s pause, got %v", *slept)
+	}
+	if !strings.Contains(log.String(), "primary rate limit") || !strings.Contains(log.String(), "0 of 5000") || !strings.Contains(log.String(), "1m31s") {
+		t.Fatalf("unexpected stderr message: %q", log.String())
+	}
+}
+
+func TestRetryClient_SecondaryRetryAfter(t *testing.T) {
+	inner := &scriptedClient{errs: []error{httpErr(429, map[string]string{"Retry-After": "30"}, "")}}
+	c, slept, _ := newTestRetryClient(inner, time.Now())
+	if err := c.Get("search/issues", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*slept) != 1 || (*slept)[0] != 30*time.Second {
+		t.Fatalf("expected a 30s pause, got %v", *slept)
+	}
+}
+
+func TestRetryClient_SecondaryWithoutHeaders(t *testing.T) {
+	inner := &scriptedClient{errs: []error{httpErr(403, nil, "You have exceeded a secondary rate limit")}}
+	c, slept, _ := newTestRetryClient(inner, time.Now())
+	if err := c.Get("x", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*slept) != 1 || (*slept)[0] != defaultRateLimitWait {
+		t.Fatalf("expected the default pause, got %v", *slept)
+	}
+}
+
+func TestRetryClient_GivesUpAfterMaxWaits(t *testing.T) {
+	limited := httpErr(429, map[string]string{"Retry-After": "1"}, "")
+	inner := &scriptedClient{errs: []error{limited, limited, limited}}
+	c, _, _ := newTestRetryClient(inner, time.Now())
+	err := c.Get("x", nil)
+	if err == nil || !strings.Contains(err.Error(), "secondary rate limit exceeded") {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+	if inner.calls != 3 {
+		t.Fatalf("expected 3 calls (2 waits), got %d", inner.calls)
+	}
+}
+
+func TestRetryClient_StatusCodes(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		calls int
+	}{
+		{"not found is final", httpErr(404, nil, "Not Found"), 1},
+		{"unauthorized is final", httpErr(401, nil, "Bad credentials"), 1},
+		{"forbidden without rate limit is final", httpErr(403, nil, "Resource not accessible"), 1},
+		{"server error retries", httpErr(502, nil, "Bad Gateway"), 3},
+		{"network error retries", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, 3},
+		{"dropped response retries", io.ErrUnexpectedEOF, 3},
+		{"graphql error is final", &ghapi.GraphQLError{Errors: []ghapi.GraphQLErrorItem{{Type: "NOT_FOUND"}}}, 1},
+		{"decode error is final", &json.SyntaxError{Offset: 1}, 1},
+		{"other local error is final", errors.New("unsupported value"), 1},
+	}
+	for _, tt := range tests {
+		inner := &scriptedClient{errs: []error{tt.err, tt.err, tt.err}}
+		c, _, _ := newTestRetryClient(inner, time.Now())
+		if err := c.Get("x", nil); err == nil {
+			t.Fatalf("%s: expected error", tt.name)
+		}
+		if inner.calls != tt.calls {
+			t.Fatalf("%s: expected %d calls, got %d", tt.name, tt.calls, inner.calls)
+		}
+	}
+}
+
+func TestRetryClient_StopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	inner := &scriptedClient{errs: []error{reset, reset}}
+	c, _, _ := newTestRetryClient(inner, time.Now())
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		cancel()
+		return sleepContext(ctx, d)
+	}
+	err := c.GetContext(ctx, "x", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if inner.calls != 1 {
+		t.Fatalf("expected no calls after cancellation, got %d", inner.calls)
+	}
+}
+
+func TestRateLimitFromError_GraphQLReset(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	limited := &ghapi.GraphQLError{Errors: []ghapi.GraphQLErrorItem{{Type: "RATE_LIMITED", Message: "API rate limit exceeded"}}}
+
+	rl, ok := rateLimitFromError(limited, now)
+	if !ok || rl.Wait != defaultRateLimitWait {
+		t.Fatalf("without headers expected the default pause, got %+v %v", rl, ok)
+	}
+
+	h := http.Header{}
+	h.Set("X-RateLimit-Limit", "5000")
+	h.Set("X-RateLimit-Remaining", "0")
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(10*time.Minute).Unix(), 10))
+	rl, ok = rateLimitFromError(&graphQLHeaderError{err: limited, headers: h}, now)
+	if !ok || rl.Wait != 10*time.Minute+time.Second || rl.Remaining != "0" || rl.Limit != "5000" {
+		t.Fatalf("expected a pause until the reset, got %+v %v", rl, ok)
+	}
+}
+
+func TestHeaderRecorder_KeepsRateLimitHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/limited" {
+			w.Header().Set("X-RateLimit-Reset", "1700000600")
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	rec := &headerRecorder{inner: http.DefaultTransport}
+	client := &http.Client{Transport: rec}
+	for _, path := range []string{"/limited", "/plain"} {
+		resp, err := client.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		resp.Body.Close()
+	}
+	// responses without rate limit headers do not replace the last ones
+	if h := rec.headers(); h == nil || h.Get("X-RateLimit-Reset") != "1700000600" {
+		t.Fatalf("unexpected recorded headers: %v", h)
+	}
+}
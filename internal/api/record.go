@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	ghapi "github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Fixture is one recorded REST response. Status is 200 for successful calls;
+// failed calls keep the status and message of the API error.
+type Fixture struct {
+	Path    string          `json:"path"`
+	Status  int             `json:"status"`
+	Message string          `json:"message,omitempty"`
+	Body    json.RawMessage `json:"body,omitempty"`
+}
+
+// recordClient passes requests to inner and saves every response as a fixture
+// file in dir. It only implements RESTClient, so listing uses the REST endpoints
+// and a recording can be replayed by ReplayClient.
+type recordClient struct {
+	inner RESTClient
+	dir   string
+}
+
+// NewRecordingClient wraps inner so that every response is also written to dir.
+func NewRecordingClient(inner RESTClient, dir string) (RESTClient, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create fixture directory: %w", err)
+	}
+	return &recordClient{inner: inner, dir: dir}, nil
+}
+
+func (r *recordClient) Get(path string, out interface{}) error {
+	return r.GetContext(context.Background(), path, out)
+}
+
+func (r *recordClient) GetContext(ctx context.Context, path string, out interface{}) error {
+	var raw json.RawMessage
+	err := r.inner.GetContext(ctx, path, &raw)
+	fx := Fixture{Path: path, Status: http.StatusOK, Body: raw}
+	if err != nil {
+		var httpErr *ghapi.HTTPError
+		if !errors.As(err, &httpErr) {
+			// network errors and cancellations are not part of the recording
+			return err
+		}
+		fx = Fixture{Path: path, Status: httpErr.StatusCode, Message: httpErr.Message}
+	}
+	b, merr := json.MarshalIndent(fx, "", "  ")
+	if merr != nil {
+		return merr
+	}
+	if werr := os.WriteFile(filepath.Join(r.dir, fixtureFileName(path)), append(b, '\n'), 0o644); werr != nil {
+		return fmt.Errorf("write fixture: %w", werr)
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+var unsafeFixtureChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// fixtureFileName derives a readable, unique file name from a request path.
+func fixtureFileName(path string) string {
+	name := strings.Trim(unsafeFixtureChars.ReplaceAllString(path, "_"), "_")
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	sum := sha256.Sum256([]byte(path))
+	return name + "-" + hex.EncodeToString(sum[:4]) + ".json"
+}
+
+// ReplayClient serves fixtures written by a recording client. Requests are
+// matched on the exact path including the query string; unknown paths fail.
+type ReplayClient struct {
+	fixtures map[string]Fixture
+}
+
+// NewReplayClient loads every *.json fixture in dir.
+func NewReplayClient(dir string) (*ReplayClient, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no fixtures found in %s", dir)
+	}
+	rc := &ReplayClient{fixtures: map[string]Fixture{}}
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		var fx Fixture
+		if err := json.Unmarshal(b, &fx); err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		if fx.Path == "" {
+			return nil, fmt.Errorf("%s: fixture has no path", f)
+		}
+		rc.fixtures[fx.Path] = fx
+	}
+	return rc, nil
+}
+
+func (rc *ReplayClient) Get(path string, out interface{}) error {
+	return rc.GetContext(context.Background(), path, out)
+}
+
+func (rc *ReplayClient) GetContext(ctx context.Context, path string, out interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fx, ok := rc.fixtures[path]
+	if !ok {
+		return fmt.Errorf("replay: no fixture for %s", path)
+	}
+	if fx.Status != http.StatusOK {
+		u, _ := url.Parse(path)
+		return &ghapi.HTTPError{StatusCode: fx.Status, Message: fx.Message, RequestURL: u}
+	}
+	return json.Unmarshal(fx.Body, out)
+}
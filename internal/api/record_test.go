@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	ghapi "github.com/cli/go-gh/v2/pkg/api"
+)
+
+// cannedClient answers known paths with a JSON body and everything else with a 404.
+type cannedClient map[string]string
+
+func (c cannedClient) Get(path string, out interface{}) error {
+	return c.GetContext(context.Background(), path, out)
+}
+
+func (c cannedClient) GetContext(ctx context.Context, path string, out interface{}) error {
+	body, ok := c[path]
+	if !ok {
+		return httpErr(http.StatusNotFound, nil, "Not Found")
+	}
+	return json.Unmarshal([]byte(body), out)
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	dir := t.TempDir()
+	rec, err := NewRecordingClient(cannedClient{"repos/o/r/issues?page=1": `[{"number":1}]`}, dir)
+	if err != nil {
+		t.Fatalf("NewRecordingClient: %v", err)
+	}
+	var items []map[string]interface{}
+	if err := rec.Get("repos/o/r/issues?page=1", &items); err != nil || len(items) != 1 {
+		t.Fatalf("recorded call: %v %v", items, err)
+	}
+	if err := rec.Get("repos/o/r/issues/9", &items); err == nil {
+		t.Fatalf("expected the 404 to be passed through")
+	}
+
+	rc, err := NewReplayClient(dir)
+	if err != nil {
+		t.Fatalf("NewReplayClient: %v", err)
+	}
+	items = nil
+	if err := rc.Get("repos/o/r/issues?page=1", &items); err != nil || len(items) != 1 || items[0]["number"] != 1.0 {
+		t.Fatalf("replayed call: %v %v", items, err)
+	}
+	var httpErr *ghapi.HTTPError
+	if err := rc.Get("repos/o/r/issues/9", &items); !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected replayed 404, got %v", err)
+	}
+	if err := rc.Get("repos/o/r/labels", &items); err == nil {
+		t.Fatalf("expected an error for a path without fixture")
+	}
+}
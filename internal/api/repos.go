@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	ghapi "github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Repository is a repository as listed for an organization or user.
+type Repository struct {
+	Name     string // repository name without the owner
+	FullName string // owner/repo
+	Archived bool
+}
+
+// restRepository is the REST repository object (only the fields we use).
+type restRepository struct {
+	Name     string `json:"name"`
+	FullName string `json:"full_name"`
+	Archived bool   `json:"archived"`
+}
+
+// ListOwnerRepos lists the repositories of an organization. When owner is not
+// an organization (the endpoint returns 404) the user's repositories are listed instead.
+func ListOwnerRepos(ctx context.Context, client RESTClient, owner string) ([]Repository, error) {
+	repos, err := listRepos(ctx, client, fmt.Sprintf("orgs/%s/repos?type=all", owner))
+	var httpErr *ghapi.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+		return listRepos(ctx, client, fmt.Sprintf("users/%s/repos?type=owner", owner))
+	}
+	return repos, err
+}
+
+func listRepos(ctx context.Context, client RESTClient, base string) ([]Repository, error) {
+	var out []Repository
+	page := 1
+	perPage := 100
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		path := fmt.Sprintf("%s&per_page=%d&page=%d", base, perPage, page)
+		var items []restRepository
+		if err := getJSON(ctx, client, path, &items); err != nil {
+			return nil, err
+		}
+		for _, it := range items {
+			out = append(out, Repository{Name: it.Name, FullName: it.FullName, Archived: it.Archived})
+		}
+		if len(items) < perPage {
+			break
+		}
+		page++
+	}
+	return out, nil
+}
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"context"
+	"testing"
+)
+
+func TestListOwnerRepos_FallsBackToUser(t *testing.T) {
+	client := cannedClient{
+		"users/octocat/repos?type=owner&per_page=100&page=1": `[{"name":"a","full_name":"octocat/a"},{"name":"old","full_name":"octocat/old","archived":true}]`,
+	}
+	repos, err := ListOwnerRepos(context.Background(), client, "octocat")
+	if err != nil {
+		t.Fatalf("ListOwnerRepos: %v", err)
+	}
+	if len(repos) != 2 || repos[0].FullName != "octocat/a" || !repos[1].Archived {
+		t.Fatalf("unexpected repositories: %+v", repos)
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// SearchResultCap is the maximum number of results the search API returns for
+// a query; SearchIssues never returns more, whatever the limit.
+const SearchResultCap = 1000
+
+// SearchIssues runs an issue search query (GitHub search syntax, for example
+// "repo:owner/repo is:issue label:bug") and returns up to limit matching issues.
+// sort may be created, updated or comments; direction is asc or desc.
+func SearchIssues(ctx context.Context, client RESTClient, query string, limit int, sort string, direction string) ([]Issue, error) {
+	var result []Issue
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > SearchResultCap {
+		limit = SearchResultCap
+	}
+
+	perPage := 100
+	page := 1
+
+	for len(result) < limit {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		qs := url.Values{}
+		qs.Set("q", query)
+		if sort != "" {
+			qs.Set("sort", sort)
+		}
+		if direction != "" {
+			qs.Set("order", direction)
+		}
+		qs.Set("per_page", strconv.Itoa(perPage))
+		qs.Set("page", strconv.Itoa(page))
+		path := fmt.Sprintf("search/issues?%s", qs.Encode())
+
+		var resp struct {
+			TotalCount        int         `json:"total_count"`
+			IncompleteResults bool        `json:"incomplete_results"`
+			Items             []restIssue `json:"items"`
+		}
+		if err := getJSON(ctx, client, path, &resp); err != nil {
+			return nil, err
+		}
+
+		if len(resp.Items) == 0 {
+			break
+		}
+		for _, it := range resp.Items {
+			if len(result) >= limit {
+				break
+			}
+			result = append(result, it.toIssue())
+		}
+
+		if len(resp.Items) < perPage || page*perPage >= resp.TotalCount {
+			break
+		}
+		page++
+	}
+	return result, nil
+}
+
+// SearchIssuesFunc is a package-level variable pointing to the SearchIssues implementation.
+// Tests can replace this with a mock function.
+var SearchIssuesFunc = SearchIssues
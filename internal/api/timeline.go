@@ -2,17 +2,18 @@ package api
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 )
 
-// TimelineEvent represents a simplified timeline event relevant to references.
+// TimelineEvent represents a simplified timeline event. SourceOwnerRepo and
+// SourceIssueNumber are set for events that reference another issue.
 type TimelineEvent struct {
 	ID                int64
 	Type              string
 	Actor             string
+	ActorIsBot        bool
 	CreatedAt         time.Time
 	SourceOwnerRepo   string
 	SourceIssueNumber int
@@ -20,98 +21,61 @@ type TimelineEvent struct {
 
 // GetIssueTimeline fetches timeline events for an issue and returns events that reference other issues.
 func GetIssueTimeline(ctx context.Context, client RESTClient, repo string, number int) ([]TimelineEvent, error) {
+	events, err := ListIssueTimeline(ctx, client, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	var out []TimelineEvent
+	for _, ev := range events {
+		// only include events that reference another issue
+		if ev.SourceIssueNumber != 0 {
+			out = append(out, ev)
+		}
+	}
+	return out, nil
+}
+
+// ListIssueTimeline fetches all timeline events of an issue (paginated), such
+// as labeled, assigned, closed and cross-referenced.
+func ListIssueTimeline(ctx context.Context, client RESTClient, repo string, number int) ([]TimelineEvent, error) {
 	var out []TimelineEvent
 	page := 1
 	perPage := 100
 	for {
-		path := fmt.Sprintf("repos/%s/issues/%d/timeline?per_page=%d&page=%d", repo, number, perPage, page)
-		var raw interface{}
-		if err := client.Get(path, &raw); err != nil {
-			return nil, err
-		}
-		body, err := json.Marshal(raw)
-		if err != nil {
+		if err := ctx.Err(); err != nil {
 			return nil, err
 		}
-		var items []map[string]interface{}
-		if err := json.Unmarshal(body, &items); err != nil {
+		path := fmt.Sprintf("repos/%s/issues/%d/timeline?per_page=%d&page=%d", repo, number, perPage, page)
+		var items []restTimelineEvent
+		if err := getJSON(ctx, client, path, &items); err != nil {
 			return nil, err
 		}
 		if len(items) == 0 {
 			break
 		}
 		for _, it := range items {
-			var ev TimelineEvent
-			if id, ok := it["id"].(float64); ok {
-				ev.ID = int64(id)
-			}
+			ev := TimelineEvent{ID: it.ID, Type: it.Event, CreatedAt: it.CreatedAt}
 			// event type may be under "event" or "type"
-			if t, ok := it["event"].(string); ok {
-				ev.Type = t
-			} else if t2, ok := it["type"].(string); ok {
-				ev.Type = t2
+			if ev.Type == "" {
+				ev.Type = it.Type
 			}
-			if a, ok := it["actor"].(map[string]interface{}); ok {
-				if login, ok := a["login"].(string); ok {
-					ev.Actor = login
-				}
+			if it.Actor != nil {
+				ev.Actor = it.Actor.Login
+				ev.ActorIsBot = it.Actor.isBot()
 			}
-			if created, ok := it["created_at"].(string); ok {
-				if tm, err := time.Parse(time.RFC3339, created); err == nil {
-					ev.CreatedAt = tm
-				}
+			// cross-referenced events describe the referencing issue in source.issue
+			if it.Source != nil && it.Source.Issue != nil {
+				ev.SourceIssueNumber = it.Source.Issue.Number
+				ev.SourceOwnerRepo = it.Source.Issue.ownerRepo()
 			}
-
-			// look for source.issue (cross-referenced)
-			if src, ok := it["source"].(map[string]interface{}); ok {
-				if iss, ok := src["issue"].(map[string]interface{}); ok {
-					if num, ok := iss["number"].(float64); ok {
-						ev.SourceIssueNumber = int(num)
-					}
-					// try to extract repository from issue.url or repository_url
-					if u, ok := iss["url"].(string); ok {
-						// url is like https://api.github.com/repos/owner/repo/issues/123
-						// try to parse owner/repo
-						// simple attempt: find "/repos/" and split
-						idx := -1
-						if p := "/repos/"; true {
-							if i := indexOf(u, p); i >= 0 {
-								idx = i + len(p)
-							}
-						}
-						if idx >= 0 {
-							tail := u[idx:]
-							// tail like owner/repo/issues/123
-							parts := splitPath(tail)
-							if len(parts) >= 2 {
-								ev.SourceOwnerRepo = parts[0] + "/" + parts[1]
-							}
-						}
-					}
-				}
-			}
-
 			// if event includes a direct "issue" field, it may be the source
-			if iss, ok := it["issue"].(map[string]interface{}); ok {
-				if num, ok := iss["number"].(float64); ok {
-					ev.SourceIssueNumber = int(num)
+			if it.Issue != nil {
+				ev.SourceIssueNumber = it.Issue.Number
+				if ev.SourceOwnerRepo == "" {
+					ev.SourceOwnerRepo = it.Issue.ownerRepo()
 				}
-				if u, ok := iss["url"].(string); ok && ev.SourceOwnerRepo == "" {
-					idx := indexOf(u, "/repos/")
-					if idx >= 0 {
-						tail := u[idx+len("/repos/"):]
-						parts := splitPath(tail)
-						if len(parts) >= 2 {
-							ev.SourceOwnerRepo = parts[0] + "/" + parts[1]
-						}
-					}
-				}
-			}
-
-			// only include events that reference another issue
-			if ev.SourceIssueNumber != 0 {
-				out = append(out, ev)
 			}
+			out = append(out, ev)
 		}
 		if len(items) < perPage {
 			break
@@ -121,9 +85,39 @@ func GetIssueTimeline(ctx context.Context, client RESTClient, repo string, numbe
 	return out, nil
 }
 
-// helper: indexOf
-func indexOf(s, sep string) int {
-	return strings.Index(s, sep)
+// restIssueRef is the issue object embedded in timeline events.
+type restIssueRef struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+}
+
+// ownerRepo extracts owner/repo from the API URL of the issue, which looks
+// like https://api.github.com/repos/owner/repo/issues/123.
+func (r restIssueRef) ownerRepo() string {
+	idx := strings.Index(r.URL, "/repos/")
+	if idx < 0 {
+		return ""
+	}
+	parts := splitPath(r.URL[idx+len("/repos/"):])
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+// restTimelineEvent is the subset of a REST timeline event we use. The timeline
+// mixes event shapes: created_at is absent on some (for example commits) and
+// is then left zero.
+type restTimelineEvent struct {
+	ID        int64     `json:"id"`
+	Event     string    `json:"event"`
+	Type      string    `json:"type"`
+	Actor     *restUser `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+	Source    *struct {
+		Issue *restIssueRef `json:"issue"`
+	} `json:"source"`
+	Issue *restIssueRef `json:"issue"`
 }
 
 // helper: splitPath, split on '/' and remove empty
@@ -0,0 +1,71 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// safeCell keeps spreadsheets from evaluating a value as a formula: text
+// starting with =, +, - or @ gets a leading apostrophe. Numbers such as -3
+// are left as they are so numeric columns stay numeric.
+func safeCell(c string) string {
+	if c == "" || !strings.ContainsRune("=+-@", rune(c[0])) {
+		return c
+	}
+	if _, err := strconv.ParseFloat(c, 64); err == nil {
+		return c
+	}
+	return "'" + c
+}
+
+// safeRow returns row with every cell passed through safeCell.
+func safeRow(row []string) []string {
+	cells := make([]string, len(row))
+	for i, c := range row {
+		cells[i] = safeCell(c)
+	}
+	return cells
+}
+
+// WriteCSV writes a header line followed by rows as comma-separated values.
+// Values that a spreadsheet would run as a formula are prefixed by safeCell.
+func WriteCSV(w io.Writer, header []string, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	for _, row := range append([][]string{header}, rows...) {
+		if err := cw.Write(safeRow(row)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// tsvReplacer keeps every value in one field: tab-separated values have no
+// quoting, so tabs and line breaks become spaces.
+var tsvReplacer = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+// WriteTSV writes a header line followed by rows as tab-separated values.
+// Values are prefixed by safeCell like those of WriteCSV.
+func WriteTSV(w io.Writer, header []string, rows [][]string) error {
+	for _, row := range append([][]string{header}, rows...) {
+		cells := safeRow(row)
+		for i, c := range cells {
+			cells[i] = tsvReplacer.Replace(c)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(cells, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteTable writes header and rows as format, which is "csv" or "tsv".
+func WriteTable(w io.Writer, format string, header []string, rows [][]string) error {
+	if format == "tsv" {
+		return WriteTSV(w, header, rows)
+	}
+	return WriteCSV(w, header, rows)
+}
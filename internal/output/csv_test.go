@@ -0,0 +1,55 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	rows := [][]string{
+		{"1", `say "hi", then`, "a\nb"},
+		{"-3", "=HYPERLINK(\"http://x\")", "@user"},
+		{"+1.5", "-rf", "+"},
+	}
+	if err := WriteCSV(&buf, []string{"n", "title", "note"}, rows); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	want := "n,title,note\n" +
+		"1,\"say \"\"hi\"\", then\",\"a\nb\"\n" +
+		"-3,\"'=HYPERLINK(\"\"http://x\"\")\",'@user\n" +
+		"+1.5,'-rf,'+\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected CSV:\n%q\nwant:\n%q", buf.String(), want)
+	}
+}
+
+func TestWriteTSV(t *testing.T) {
+	var buf bytes.Buffer
+	rows := [][]string{
+		{"1", "tab\there", "two\r\nlines\nmore"},
+		{"-2", "=1+1", "@bot"},
+	}
+	if err := WriteTSV(&buf, []string{"n", "title", "body"}, rows); err != nil {
+		t.Fatalf("WriteTSV: %v", err)
+	}
+	want := "n\ttitle\tbody\n" +
+		"1\ttab here\ttwo lines more\n" +
+		"-2\t'=1+1\t'@bot\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected TSV:\n%q\nwant:\n%q", buf.String(), want)
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	var csvBuf, tsvBuf bytes.Buffer
+	if err := WriteTable(&csvBuf, "csv", []string{"a", "b"}, [][]string{{"1", "2"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteTable(&tsvBuf, "tsv", []string{"a", "b"}, [][]string{{"1", "2"}}); err != nil {
+		t.Fatal(err)
+	}
+	if csvBuf.String() != "a,b\n1,2\n" || tsvBuf.String() != "a\tb\n1\t2\n" {
+		t.Fatalf("unexpected tables: %q, %q", csvBuf.String(), tsvBuf.String())
+	}
+}
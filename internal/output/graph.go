@@ -0,0 +1,65 @@
+package output
+
+import "time"
+
+// Graph is the reference graph in a form every graph writer can use: the
+// nodes with what is known about their issues, and the edges between them.
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is an issue of the graph, keyed by ID (owner/repo#N, or
+// HOST/OWNER/REPO#N off the default host). The issue fields are empty when the
+// issue was not fetched, for example a reference beyond the traversal depth.
+// Depth is the number of hops from the nearest seed issue. Visited is set for
+// the nodes the traversal reached, whose references were followed unless
+// FetchError says why the issue could not be fetched.
+type GraphNode struct {
+	ID         string     `json:"id"`
+	Repo       string     `json:"repo"`
+	Number     int        `json:"number"`
+	Title      string     `json:"title,omitempty"`
+	State      string     `json:"state,omitempty"`
+	URL        string     `json:"url,omitempty"`
+	Labels     []string   `json:"labels,omitempty"`
+	Author     string     `json:"author,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	ClosedAt   *time.Time `json:"closed_at,omitempty"`
+	IsPR       bool       `json:"is_pr"`
+	Depth      int        `json:"depth"`
+	Visited    bool       `json:"visited"`
+	FetchError string     `json:"fetch_error,omitempty"`
+}
+
+// GraphEdge is a reference from the issue From to the issue To. Source is
+// where it was found: timeline, comment or body. Action is the timeline event
+// type, such as cross-referenced.
+type GraphEdge struct {
+	From      string     `json:"from"`
+	To        string     `json:"to"`
+	Source    string     `json:"source"`
+	Actor     string     `json:"actor,omitempty"`
+	Action    string     `json:"action,omitempty"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	CommentID int64      `json:"comment_id,omitempty"`
+}
+
+// GraphSchemaVersion is the version of the graph JSON document. Version 1 was
+// the adjacency map {"owner/repo#N": [edges]}, which graph --json-version 1
+// still writes.
+const GraphSchemaVersion = 2
+
+// GraphStats describes the traversal that built a graph. NodesTruncated
+// counts the nodes left out because of --max-nodes (LimitHit); APICalls
+// counts the HTTP requests the command sent to the API (see api.Requests).
+type GraphStats struct {
+	Seeds          int   `json:"seeds"`
+	Depth          int   `json:"depth"`
+	MaxNodes       int   `json:"max_nodes"`
+	NodesVisited   int   `json:"nodes_visited"`
+	NodesTruncated int   `json:"nodes_truncated"`
+	LimitHit       bool  `json:"limit_hit"`
+	APICalls       int64 `json:"api_calls"`
+	Interrupted    bool  `json:"interrupted"`
+}
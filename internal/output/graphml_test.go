@@ -0,0 +1,92 @@
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testExportGraph() *Graph {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	return &Graph{
+		Nodes: []GraphNode{
+			{ID: "o/a#1", Repo: "o/a", Number: 1, Title: `Crash on "save" & <exit>`, State: "closed", Labels: []string{"bug", "ui"}, Author: "alice", CreatedAt: &created, ClosedAt: &created},
+			{ID: "o/b#7", Repo: "o/b", Number: 7, Depth: 1},
+		},
+		Edges: []GraphEdge{
+			{From: "o/a#1", To: "o/b#7", Source: "comment", Actor: "dave", Timestamp: &created, CommentID: 42},
+			{From: "o/a#1", To: "o/missing#9", Source: "body"},
+		},
+	}
+}
+
+// wellFormed fails the test unless out parses as XML.
+func wellFormed(t *testing.T, out string) {
+	t.Helper()
+	d := xml.NewDecoder(strings.NewReader(out))
+	for {
+		if _, err := d.Token(); err == io.EOF {
+			return
+		} else if err != nil {
+			t.Fatalf("malformed XML: %v\n%s", err, out)
+		}
+	}
+}
+
+func TestWriteGraphGraphML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGraphGraphML(&buf, testExportGraph()); err != nil {
+		t.Fatalf("WriteGraphGraphML: %v", err)
+	}
+	out := buf.String()
+	wellFormed(t, out)
+	for _, want := range []string{
+		`<key id="number" for="node" attr.name="number" attr.type="int"/>`,
+		`<key id="e_comment_id" for="edge" attr.name="comment_id" attr.type="long"/>`,
+		`<node id="o/a#1">`,
+		`<data key="title">Crash on &#34;save&#34; &amp; &lt;exit&gt;</data>`,
+		`<data key="labels">bug,ui</data>`,
+		`<data key="created_at">2025-03-01T12:00:00Z</data>`,
+		`<data key="depth">0</data>`,
+		`<edge id="e0" source="o/a#1" target="o/b#7">`,
+		`<data key="e_comment_id">42</data>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("graphml output lacks %q:\n%s", want, out)
+		}
+	}
+	// unset attributes are left out, as are edges to undeclared nodes
+	for _, unwanted := range []string{`<data key="title"></data>`, "o/missing#9"} {
+		if strings.Contains(out, unwanted) {
+			t.Fatalf("graphml output contains %q:\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestWriteGraphGEXF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGraphGEXF(&buf, testExportGraph()); err != nil {
+		t.Fatalf("WriteGraphGEXF: %v", err)
+	}
+	out := buf.String()
+	wellFormed(t, out)
+	for _, want := range []string{
+		`<attribute id="depth" title="depth" type="integer"/>`,
+		`<attribute id="comment_id" title="comment_id" type="long"/>`,
+		`<node id="o/b#7" label="o/b#7">`,
+		`<attvalue for="depth" value="1"/>`,
+		`<attvalue for="author" value="alice"/>`,
+		`<edge id="0" source="o/a#1" target="o/b#7" label="comment">`,
+		`<attvalue for="actor" value="dave"/>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("gexf output lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "o/missing#9") {
+		t.Fatalf("gexf output has an edge to an undeclared node:\n%s", out)
+	}
+}
@@ -0,0 +1,220 @@
+package output
+
+import (
+	_ "embed"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+// HTMLReport is the content of a self-contained HTML report. Every section is
+// optional; empty ones are left out.
+type HTMLReport struct {
+	Title      string
+	Repository string
+	Generated  time.Time
+	Filters    []string
+	Stats      []HTMLStat
+	Trend      *HTMLTrend
+	Bars       []HTMLBars
+	Tables     []HTMLTable
+	Graph      *Graph
+}
+
+// HTMLStat is one headline number.
+type HTMLStat struct {
+	Label  string
+	Value  string
+	Detail string
+}
+
+// HTMLTrend is a series of periods drawn as an opened/closed bar chart and a
+// backlog line chart.
+type HTMLTrend struct {
+	Title   string
+	Periods []HTMLPeriod
+}
+
+// HTMLPeriod is one bucket of an HTMLTrend.
+type HTMLPeriod struct {
+	Label   string
+	Opened  int
+	Closed  int
+	Backlog int
+}
+
+// HTMLBars is a distribution drawn as horizontal bars, such as issues per label.
+type HTMLBars struct {
+	Title string
+	Total int // the share of each bar is relative to Total
+	Bars  []HTMLBar
+}
+
+// HTMLBar is one bar of an HTMLBars.
+type HTMLBar struct {
+	Label string
+	Count int
+}
+
+// HTMLTable is a table with optional links in its cells. Numeric columns are
+// right-aligned.
+type HTMLTable struct {
+	Title   string
+	Header  []string
+	Numeric []bool
+	Rows    [][]HTMLCell
+}
+
+// HTMLCell is a table cell, linked when URL is set.
+type HTMLCell struct {
+	Text string
+	URL  string
+}
+
+//go:embed report.html
+var reportTemplate string
+
+//go:embed report.css
+var reportCSS string
+
+//go:embed report.js
+var reportJS string
+
+var reportFuncs = template.FuncMap{
+	"join": strings.Join,
+	"percent": func(n, total int) string {
+		if total == 0 {
+			return "0%"
+		}
+		return fmt.Sprintf("%.0f%%", float64(n)*100/float64(total))
+	},
+	// width is the bar length in percent of the largest bar
+	"width": func(n int, bars []HTMLBar) float64 {
+		max := 0
+		for _, b := range bars {
+			if b.Count > max {
+				max = b.Count
+			}
+		}
+		if max == 0 {
+			return 0
+		}
+		return float64(n) * 100 / float64(max)
+	},
+	"css":          func() template.CSS { return template.CSS(reportCSS) },
+	"js":           func() template.JS { return template.JS(reportJS) },
+	"trendChart":   trendChart,
+	"backlogChart": backlogChart,
+}
+
+var reportTmpl = template.Must(template.New("report").Funcs(reportFuncs).Parse(reportTemplate))
+
+// WriteHTMLReport writes r as a single HTML document. Styles, charts, data and
+// the graph script are inline, so the file needs no server or network access.
+func WriteHTMLReport(w io.Writer, r HTMLReport) error {
+	return reportTmpl.Execute(w, r)
+}
+
+// chart geometry in SVG user units; the SVG scales to the page width
+const (
+	chartWidth  = 720
+	chartHeight = 220
+	chartLeft   = 40
+	chartBottom = 30
+	chartTop    = 10
+)
+
+// chartScale returns the largest value of vs rounded up to a readable step.
+func chartScale(vs []int) int {
+	max := 1
+	for _, v := range vs {
+		if v > max {
+			max = v
+		}
+	}
+	for _, step := range []int{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000} {
+		if max <= step {
+			return step
+		}
+	}
+	return max
+}
+
+// chartFrame writes the y axis with its scale and every nth period label.
+func chartFrame(b *strings.Builder, periods []HTMLPeriod, max int, slot float64) {
+	plotH := float64(chartHeight - chartBottom - chartTop)
+	for _, f := range []float64{0, 0.5, 1} {
+		y := chartTop + plotH*(1-f)
+		fmt.Fprintf(b, `<line class="grid" x1="%d" y1="%.1f" x2="%d" y2="%.1f"/>`, chartLeft, y, chartWidth, y)
+		fmt.Fprintf(b, `<text class="axis" x="%d" y="%.1f" text-anchor="end">%d</text>`, chartLeft-4, y+4, int(float64(max)*f))
+	}
+	every := (len(periods) + 7) / 8
+	for i, p := range periods {
+		if i%every != 0 {
+			continue
+		}
+		x := chartLeft + slot*(float64(i)+0.5)
+		fmt.Fprintf(b, `<text class="axis" x="%.1f" y="%d" text-anchor="middle">%s</text>`, x, chartHeight-10, html.EscapeString(p.Label))
+	}
+}
+
+// trendChart draws the opened and closed issues of each period as paired bars.
+func trendChart(t *HTMLTrend) template.HTML {
+	var vs []int
+	for _, p := range t.Periods {
+		vs = append(vs, p.Opened, p.Closed)
+	}
+	max := chartScale(vs)
+	slot := float64(chartWidth-chartLeft) / float64(max1(len(t.Periods)))
+	plotH := float64(chartHeight - chartBottom - chartTop)
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="chart" viewBox="0 0 %d %d" role="img" aria-label="Opened and closed issues per period">`, chartWidth, chartHeight)
+	chartFrame(&b, t.Periods, max, slot)
+	barW := slot * 0.38
+	for i, p := range t.Periods {
+		x := chartLeft + slot*float64(i) + slot*0.1
+		for j, v := range []int{p.Opened, p.Closed} {
+			h := plotH * float64(v) / float64(max)
+			class := []string{"opened", "closed"}[j]
+			fmt.Fprintf(&b, `<rect class="%s" x="%.1f" y="%.1f" width="%.1f" height="%.1f"><title>%s: %d %s</title></rect>`,
+				class, x+float64(j)*barW, chartTop+plotH-h, barW, h, html.EscapeString(p.Label), v, class)
+		}
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// backlogChart draws the backlog at the end of each period as a line.
+func backlogChart(t *HTMLTrend) template.HTML {
+	periods := t.Periods
+	var vs []int
+	for _, p := range periods {
+		vs = append(vs, p.Backlog)
+	}
+	max := chartScale(vs)
+	slot := float64(chartWidth-chartLeft) / float64(max1(len(periods)))
+	plotH := float64(chartHeight - chartBottom - chartTop)
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="chart" viewBox="0 0 %d %d" role="img" aria-label="Backlog at the end of each period">`, chartWidth, chartHeight)
+	chartFrame(&b, periods, max, slot)
+	var points []string
+	for i, p := range periods {
+		x := chartLeft + slot*(float64(i)+0.5)
+		y := chartTop + plotH*(1-float64(p.Backlog)/float64(max))
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+		fmt.Fprintf(&b, `<circle class="backlog" cx="%.1f" cy="%.1f" r="3"><title>%s: %d open</title></circle>`, x, y, html.EscapeString(p.Label), p.Backlog)
+	}
+	fmt.Fprintf(&b, `<polyline class="backlog" points="%s"/>`, strings.Join(points, " "))
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+func max1(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
@@ -0,0 +1,79 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteHTMLReport(t *testing.T) {
+	r := HTMLReport{
+		Title:      "Issues <b>",
+		Repository: "o/a",
+		Generated:  time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Filters:    []string{"label=bug", "state=open"},
+		Stats:      []HTMLStat{{Label: "Open", Value: "3", Detail: "of 4"}},
+		Bars: []HTMLBars{{Title: "Labels", Total: 4, Bars: []HTMLBar{
+			{Label: "bug", Count: 2},
+			{Label: "docs", Count: 1},
+		}}},
+		Tables: []HTMLTable{{
+			Title:   "Oldest",
+			Header:  []string{"Issue", "Days"},
+			Numeric: []bool{false, true},
+			Rows: [][]HTMLCell{
+				{{Text: "#1 <script>", URL: "https://example.com/o/a/issues/1"}, {Text: "12"}},
+				{{Text: "#2"}, {Text: "3"}},
+			},
+		}},
+		Graph: &Graph{
+			Nodes: []GraphNode{{ID: "o/a#1", Title: "</script><b>"}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := WriteHTMLReport(&buf, r); err != nil {
+		t.Fatalf("WriteHTMLReport: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"<title>Issues &lt;b&gt;</title>",
+		"o/a, generated 2024-05-01 12:00 UTC",
+		"Filters: label=bug, state=open",
+		`<div class="value">3</div><div class="label">Open</div><div class="detail">of 4</div>`,
+		`<td class="name">bug</td><td class="bar"><span style="width: 100%"></span></td><td class="num">2</td><td class="num">50%</td>`,
+		`<span style="width: 50%"></span></td><td class="num">1</td><td class="num">25%</td>`,
+		`<th>Issue</th><th class="num">Days</th>`,
+		`<td><a href="https://example.com/o/a/issues/1">#1 &lt;script&gt;</a></td><td class="num">12</td>`,
+		`<td>#2</td><td class="num">3</td>`,
+		`"title":"\u003c/script\u003e\u003cb\u003e"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("report lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `<svg class="chart"`) {
+		t.Fatalf("expected no charts without a trend:\n%s", out)
+	}
+}
+
+func TestWriteHTMLReport_Trend(t *testing.T) {
+	r := HTMLReport{
+		Title: "Trend",
+		Trend: &HTMLTrend{Title: "Weekly Trend", Periods: []HTMLPeriod{
+			{Label: "May 1", Opened: 4, Closed: 1, Backlog: 3},
+			{Label: "May 8", Opened: 2, Closed: 3, Backlog: 2},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := WriteHTMLReport(&buf, r); err != nil {
+		t.Fatalf("WriteHTMLReport: %v", err)
+	}
+	out := buf.String()
+	if strings.Count(out, `<svg class="chart"`) != 2 || !strings.Contains(out, "<h2>Weekly Trend</h2>") {
+		t.Fatalf("expected the opened/closed and backlog charts:\n%s", out)
+	}
+	if strings.Contains(out, "graphData") || strings.Contains(out, "Filters:") {
+		t.Fatalf("expected empty sections to be left out:\n%s", out)
+	}
+}
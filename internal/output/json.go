@@ -5,23 +5,57 @@ import (
 	"io"
 )
 
-// WriteFetchJSON writes fetch results as JSON: { repository: <repo>, issues: [...] }
-func WriteFetchJSON(w io.Writer, repo string, issues interface{}) error {
+// FetchDocument is the value WriteFetchJSON encodes: { repository: <repo>, issues: [...] }
+func FetchDocument(repo string, issues interface{}) map[string]interface{} {
+	return map[string]interface{}{"repository": repo, "issues": issues}
+}
+
+// PulseDocument is the value WritePulseJSON encodes: { repository: <repo>, metrics: {...} }
+func PulseDocument(repo string, metrics interface{}) map[string]interface{} {
+	return map[string]interface{}{"repository": repo, "metrics": metrics}
+}
+
+// PulseGroupsDocument is the value WritePulseGroupsJSON encodes:
+// { repository: <repo>, metrics: {...}, group_by: <field>, groups: [...] }
+func PulseGroupsDocument(repo string, metrics interface{}, groupBy string, groups interface{}) map[string]interface{} {
+	return map[string]interface{}{"repository": repo, "metrics": metrics, "group_by": groupBy, "groups": groups}
+}
+
+// GraphDocument is the value graph --format json encodes:
+// { version: 2, repository: <repo>, nodes: [...], edges: [...], stats: {...} }
+func GraphDocument(repo string, g *Graph, stats GraphStats) map[string]interface{} {
+	return map[string]interface{}{"version": GraphSchemaVersion, "repository": repo, "nodes": g.Nodes, "edges": g.Edges, "stats": stats}
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
 	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
-	return enc.Encode(map[string]interface{}{"repository": repo, "issues": issues})
+	return enc.Encode(v)
+}
+
+// WriteFetchJSON writes fetch results as JSON: { repository: <repo>, issues: [...] }
+func WriteFetchJSON(w io.Writer, repo string, issues interface{}) error {
+	return writeJSON(w, FetchDocument(repo, issues))
 }
 
 // WritePulseJSON writes pulse metrics as JSON: { repository: <repo>, metrics: {...} }
 func WritePulseJSON(w io.Writer, repo string, metrics interface{}) error {
-	enc := json.NewEncoder(w)
-	enc.SetIndent("", "  ")
-	return enc.Encode(map[string]interface{}{"repository": repo, "metrics": metrics})
+	return writeJSON(w, PulseDocument(repo, metrics))
+}
+
+// WriteCompareJSON writes a comparison as JSON: { columns: [...], rows: [...] }
+func WriteCompareJSON(w io.Writer, comparison interface{}) error {
+	return writeJSON(w, comparison)
 }
 
-// WriteGraphJSON writes arbitrary graph data (typically map[string][]edge) as JSON
+// WriteGraphJSON writes graph data (a GraphDocument, or the version 1
+// map[string][]edge adjacency) as JSON
 func WriteGraphJSON(w io.Writer, v interface{}) error {
-	enc := json.NewEncoder(w)
-	enc.SetIndent("", "  ")
-	return enc.Encode(v)
+	return writeJSON(w, v)
+}
+
+// WritePulseGroupsJSON writes grouped pulse metrics as JSON:
+// { repository: <repo>, metrics: {...}, group_by: <field>, groups: [...] }
+func WritePulseGroupsJSON(w io.Writer, repo string, metrics interface{}, groupBy string, groups interface{}) error {
+	return writeJSON(w, PulseGroupsDocument(repo, metrics, groupBy, groups))
 }
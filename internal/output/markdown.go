@@ -0,0 +1,80 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// escapeMarkdownCell keeps cell text on one line and escapes the column separator.
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\r", "")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// WriteMarkdownTable writes a GitHub-flavored Markdown table. align holds one
+// of "", "left", "right" or "center" per column (missing entries are default).
+func WriteMarkdownTable(w io.Writer, header []string, align []string, rows [][]string) error {
+	cells := make([]string, len(header))
+	for i, h := range header {
+		cells[i] = escapeMarkdownCell(h)
+	}
+	if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | ")); err != nil {
+		return err
+	}
+	for i := range header {
+		a := ""
+		if i < len(align) {
+			a = align[i]
+		}
+		switch a {
+		case "left":
+			cells[i] = ":---"
+		case "right":
+			cells[i] = "---:"
+		case "center":
+			cells[i] = ":---:"
+		default:
+			cells[i] = "---"
+		}
+	}
+	if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | ")); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		for i := range cells {
+			cells[i] = ""
+			if i < len(row) {
+				cells[i] = escapeMarkdownCell(row[i])
+			}
+		}
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markdownEscaper escapes the characters that would otherwise start emphasis,
+// code, links, HTML or a mention inside inline text.
+var markdownEscaper = strings.NewReplacer(
+	"\\", "\\\\", "*", "\\*", "_", "\\_", "`", "\\`",
+	"[", "\\[", "]", "\\]", "<", "&lt;", ">", "&gt;", "@", "@\u200b",
+)
+
+// EscapeMarkdown makes s render literally in Markdown, for example an issue
+// title or a label name. Mentions are broken with a zero-width space so a
+// posted report does not notify the people it names.
+func EscapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// MarkdownLink renders an inline link with escaped text; without a URL only
+// the text is written.
+func MarkdownLink(text, url string) string {
+	if url == "" {
+		return EscapeMarkdown(text)
+	}
+	return "[" + EscapeMarkdown(text) + "](" + strings.ReplaceAll(url, ")", "%29") + ")"
+}
@@ -0,0 +1,51 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEscapeMarkdown(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain title", "plain title"},
+		{"*bold* and _em_", `\*bold\* and \_em\_`},
+		{"`code` [link](x)", "\\`code\\` \\[link\\](x)"},
+		{`C:\path`, `C:\\path`},
+		{"<script>", "&lt;script&gt;"},
+		{"ping @alice", "ping @\u200balice"},
+	}
+	for _, tt := range tests {
+		if got := EscapeMarkdown(tt.in); got != tt.want {
+			t.Fatalf("EscapeMarkdown(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMarkdownLink(t *testing.T) {
+	if got := MarkdownLink("#1 [x]", "https://e.com/a_(b)"); got != `[#1 \[x\]](https://e.com/a_(b%29)` {
+		t.Fatalf("unexpected link: %q", got)
+	}
+	if got := MarkdownLink("#1", ""); got != "#1" {
+		t.Fatalf("expected plain text without a URL, got %q", got)
+	}
+}
+
+func TestWriteMarkdownTable(t *testing.T) {
+	var buf bytes.Buffer
+	rows := [][]string{
+		{"a|b", "1", "x"},
+		{"two\r\nlines", "2"},
+	}
+	if err := WriteMarkdownTable(&buf, []string{"Name", "Count", "Note"}, []string{"left", "right"}, rows); err != nil {
+		t.Fatalf("WriteMarkdownTable: %v", err)
+	}
+	want := "| Name | Count | Note |\n" +
+		"| :--- | ---: | --- |\n" +
+		"| a\\|b | 1 | x |\n" +
+		"| two lines | 2 |  |\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected table:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
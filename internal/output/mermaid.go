@@ -0,0 +1,113 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// mermaidEscaper replaces the characters that end or reinterpret a quoted
+// Mermaid label with entity codes. '#' starts an entity code itself, so it is
+// escaped first; line breaks become spaces.
+var mermaidEscaper = strings.NewReplacer(
+	"#", "#35;", "\"", "#quot;", "<", "#lt;", ">", "#gt;", "`", "#96;",
+	"\r", "", "\n", " ",
+)
+
+// mermaidArrows draws the edges of each source differently: timeline events
+// are solid, comments dotted and issue bodies thick.
+var mermaidArrows = map[string]string{
+	"timeline": "-->",
+	"comment":  "-.->",
+	"body":     "==>",
+}
+
+// mermaidActionColors colors timeline edges by their event type.
+var mermaidActionColors = map[string]string{
+	"cross-referenced":    "#0969da",
+	"connected":           "#1a7f37",
+	"referenced":          "#8250df",
+	"marked_as_duplicate": "#cf222e",
+}
+
+// mermaidTitleLength bounds node titles so the diagram stays readable.
+const mermaidTitleLength = 40
+
+// WriteGraphMermaid writes g as a Mermaid flowchart, which GitHub renders in
+// Markdown inside a ```mermaid block. Nodes are labeled with the issue number
+// (owner/repo#N when the graph spans repositories) and title and classed by
+// state; edges are labeled with the action or source and the actor.
+func WriteGraphMermaid(w io.Writer, g *Graph) error {
+	ids := map[string]string{}
+	repos := map[string]bool{}
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+		repos[n.Repo] = true
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, n := range g.Nodes {
+		label := n.ID
+		if len(repos) == 1 && n.Number > 0 {
+			label = fmt.Sprintf("#%d", n.Number)
+		}
+		if n.Title != "" {
+			title := []rune(n.Title)
+			if len(title) > mermaidTitleLength {
+				title = append(title[:mermaidTitleLength-1], '…')
+			}
+			label += " " + string(title)
+		}
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[n.ID], mermaidEscaper.Replace(label))
+	}
+
+	// linkStyle addresses edges by the order they are written in
+	var styles []string
+	link := 0
+	for _, e := range g.Edges {
+		from, ok1 := ids[e.From]
+		to, ok2 := ids[e.To]
+		if !ok1 || !ok2 {
+			continue
+		}
+		arrow, ok := mermaidArrows[e.Source]
+		if !ok {
+			arrow = "-->"
+		}
+		label := e.Source
+		if e.Action != "" {
+			label = e.Action
+		}
+		if e.Actor != "" {
+			label += " by " + e.Actor
+		}
+		fmt.Fprintf(&b, "  %s %s|\"%s\"| %s\n", from, arrow, mermaidEscaper.Replace(label), to)
+		if color, ok := mermaidActionColors[e.Action]; ok {
+			styles = append(styles, fmt.Sprintf("  linkStyle %d stroke:%s", link, color))
+		}
+		link++
+	}
+	for _, s := range styles {
+		b.WriteString(s + "\n")
+	}
+
+	b.WriteString("  classDef open fill:#dafbe1,stroke:#1a7f37\n")
+	b.WriteString("  classDef closed fill:#fbefff,stroke:#8250df\n")
+	b.WriteString("  classDef unknown fill:#f6f8fa,stroke:#8c959f,stroke-dasharray:3 3\n")
+	byState := map[string][]string{}
+	for _, n := range g.Nodes {
+		state := n.State
+		if state != "open" && state != "closed" {
+			state = "unknown"
+		}
+		byState[state] = append(byState[state], ids[n.ID])
+	}
+	for _, state := range []string{"open", "closed", "unknown"} {
+		if len(byState[state]) > 0 {
+			fmt.Fprintf(&b, "  class %s %s\n", strings.Join(byState[state], ","), state)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,44 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteGraphMermaid(t *testing.T) {
+	g := &Graph{
+		Nodes: []GraphNode{
+			{ID: "o/a#1", Repo: "o/a", Number: 1, Title: `Crash on "save" <script> #2`, State: "closed"},
+			{ID: "o/b#7", Repo: "o/b", Number: 7},
+		},
+		Edges: []GraphEdge{
+			{From: "o/a#1", To: "o/b#7", Source: "comment", Actor: "dave"},
+			{From: "o/a#1", To: "o/missing#9", Source: "body"},
+			{From: "o/b#7", To: "o/a#1", Source: "timeline", Action: "connected"},
+		},
+	}
+	var buf bytes.Buffer
+	if err := WriteGraphMermaid(&buf, g); err != nil {
+		t.Fatalf("WriteGraphMermaid: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		// two repositories: labels carry the full key, special characters are entity codes
+		`  n0["o/a#35;1 Crash on #quot;save#quot; #lt;script#gt; #35;2"]`,
+		`  n1["o/b#35;7"]`,
+		`  n0 -.->|"comment by dave"| n1`,
+		`  n1 -->|"connected"| n0`,
+		// the edge to an unknown node is skipped, so the styled link is the second
+		"  linkStyle 1 stroke:#1a7f37\n",
+		"  class n0 closed\n",
+		"  class n1 unknown\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "missing") {
+		t.Fatalf("edge to a node outside the graph was written:\n%s", out)
+	}
+}
@@ -0,0 +1,192 @@
+// Draws graphData (a Graph: {nodes, edges}) into svg#graph with a force-directed
+// layout. Dragging the background pans, the wheel zooms, dragging a node moves
+// it and clicking a node opens its issue.
+(function () {
+  "use strict";
+  var NS = "http://www.w3.org/2000/svg";
+  var svg = document.getElementById("graph");
+  if (!svg || !graphData.nodes.length) {
+    return;
+  }
+
+  function el(name, attrs, parent) {
+    var e = document.createElementNS(NS, name);
+    for (var k in attrs) {
+      e.setAttribute(k, attrs[k]);
+    }
+    parent.appendChild(e);
+    return e;
+  }
+
+  var width = svg.clientWidth || 1000;
+  var height = svg.clientHeight || 600;
+  var nodes = graphData.nodes.map(function (n, i) {
+    // start on a circle so the layout is the same on every load
+    var a = (2 * Math.PI * i) / graphData.nodes.length;
+    var r = Math.min(width, height) / 3;
+    return { data: n, x: width / 2 + r * Math.cos(a), y: height / 2 + r * Math.sin(a), vx: 0, vy: 0, degree: 0 };
+  });
+  var byId = {};
+  nodes.forEach(function (n) {
+    byId[n.data.id] = n;
+  });
+  var edges = [];
+  graphData.edges.forEach(function (e) {
+    var s = byId[e.from];
+    var t = byId[e.to];
+    if (s && t && s !== t) {
+      s.degree++;
+      t.degree++;
+      edges.push({ data: e, s: s, t: t });
+    }
+  });
+
+  // a fixed number of simulation steps keeps large graphs responsive
+  var steps = nodes.length > 300 ? 150 : 300;
+  for (var step = 0; step < steps; step++) {
+    var alpha = 1 - step / steps;
+    for (var i = 0; i < nodes.length; i++) {
+      var a = nodes[i];
+      for (var j = i + 1; j < nodes.length; j++) {
+        var b = nodes[j];
+        var dx = a.x - b.x;
+        var dy = a.y - b.y;
+        var d2 = dx * dx + dy * dy || 0.01;
+        if (d2 > 90000) {
+          continue;
+        }
+        var f = (900 * alpha) / d2;
+        a.vx += dx * f;
+        a.vy += dy * f;
+        b.vx -= dx * f;
+        b.vy -= dy * f;
+      }
+    }
+    edges.forEach(function (e) {
+      var dx = e.t.x - e.s.x;
+      var dy = e.t.y - e.s.y;
+      var d = Math.sqrt(dx * dx + dy * dy) || 0.01;
+      var f = ((d - 60) / d) * 0.05 * alpha;
+      e.s.vx += dx * f;
+      e.s.vy += dy * f;
+      e.t.vx -= dx * f;
+      e.t.vy -= dy * f;
+    });
+    nodes.forEach(function (n) {
+      n.vx += (width / 2 - n.x) * 0.02 * alpha;
+      n.vy += (height / 2 - n.y) * 0.02 * alpha;
+      n.x += Math.max(-20, Math.min(20, n.vx));
+      n.y += Math.max(-20, Math.min(20, n.vy));
+      n.vx *= 0.6;
+      n.vy *= 0.6;
+    });
+  }
+
+  var defs = el("defs", {}, svg);
+  ["timeline", "comment", "body"].forEach(function (kind) {
+    var m = el("marker", { id: "arrow-" + kind, viewBox: "0 0 10 10", refX: "18", refY: "5", markerWidth: "6", markerHeight: "6", orient: "auto" }, defs);
+    el("path", { d: "M0,0 L10,5 L0,10 z", class: kind }, m);
+  });
+  var view = el("g", {}, svg);
+  edges.forEach(function (e) {
+    var kind = e.data.source || "body";
+    e.line = el("line", { class: kind, "marker-end": "url(#arrow-" + kind + ")" }, view);
+    var title = el("title", {}, e.line);
+    title.textContent = e.data.from + " -> " + e.data.to + " (" + kind + (e.data.action ? ", " + e.data.action : "") + (e.data.actor ? ", " + e.data.actor : "") + ")";
+  });
+  nodes.forEach(function (n) {
+    n.g = el("g", { class: "node " + (n.data.state || "unknown") }, view);
+    el("circle", { r: String(5 + Math.min(8, n.degree)) }, n.g);
+    var label = el("text", { x: "10", y: "4" }, n.g);
+    label.textContent = n.data.id.replace(/^.*\//, "");
+    var title = el("title", {}, n.g);
+    title.textContent = n.data.id + (n.data.title ? ": " + n.data.title : "") + (n.data.state ? " (" + n.data.state + ")" : "");
+  });
+
+  function draw() {
+    edges.forEach(function (e) {
+      e.line.setAttribute("x1", e.s.x);
+      e.line.setAttribute("y1", e.s.y);
+      e.line.setAttribute("x2", e.t.x);
+      e.line.setAttribute("y2", e.t.y);
+    });
+    nodes.forEach(function (n) {
+      n.g.setAttribute("transform", "translate(" + n.x + "," + n.y + ")");
+    });
+  }
+  draw();
+
+  // pan and zoom apply one transform to the whole view, which starts out
+  // fitting every node
+  var minX = Infinity;
+  var minY = Infinity;
+  var maxX = -Infinity;
+  var maxY = -Infinity;
+  nodes.forEach(function (n) {
+    minX = Math.min(minX, n.x);
+    minY = Math.min(minY, n.y);
+    maxX = Math.max(maxX, n.x);
+    maxY = Math.max(maxY, n.y);
+  });
+  var scale = Math.min(2, width / (maxX - minX + 80), height / (maxY - minY + 80));
+  var tx = width / 2 - ((minX + maxX) / 2) * scale;
+  var ty = height / 2 - ((minY + maxY) / 2) * scale;
+  function applyView() {
+    view.setAttribute("transform", "translate(" + tx + "," + ty + ") scale(" + scale + ")");
+  }
+  applyView();
+  function point(ev) {
+    var r = svg.getBoundingClientRect();
+    return { x: ev.clientX - r.left, y: ev.clientY - r.top };
+  }
+  svg.addEventListener("wheel", function (ev) {
+    ev.preventDefault();
+    var p = point(ev);
+    var k = ev.deltaY < 0 ? 1.15 : 1 / 1.15;
+    tx = p.x - (p.x - tx) * k;
+    ty = p.y - (p.y - ty) * k;
+    scale *= k;
+    applyView();
+  }, { passive: false });
+
+  var drag = null;
+  svg.addEventListener("pointerdown", function (ev) {
+    var target = null;
+    nodes.forEach(function (n) {
+      if (n.g.contains(ev.target)) {
+        target = n;
+      }
+    });
+    drag = { node: target, start: point(ev), moved: false, tx: tx, ty: ty };
+    svg.setPointerCapture(ev.pointerId);
+  });
+  svg.addEventListener("pointermove", function (ev) {
+    if (!drag) {
+      return;
+    }
+    var p = point(ev);
+    var dx = p.x - drag.start.x;
+    var dy = p.y - drag.start.y;
+    if (Math.abs(dx) + Math.abs(dy) > 3) {
+      drag.moved = true;
+    }
+    if (drag.node) {
+      drag.node.x = (p.x - tx) / scale;
+      drag.node.y = (p.y - ty) / scale;
+      draw();
+    } else {
+      tx = drag.tx + dx;
+      ty = drag.ty + dy;
+      applyView();
+    }
+  });
+  svg.addEventListener("pointerup", function () {
+    if (drag && drag.node && !drag.moved) {
+      var url = drag.node.data.url || "";
+      if (/^https?:\/\//.test(url)) {
+        window.open(url, "_blank", "noopener");
+      }
+    }
+    drag = null;
+  });
+})();
@@ -0,0 +1,43 @@
+package output
+
+import "strings"
+
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a row of block characters scaled between the
+// smallest and largest value. Equal values render as the lowest block.
+func Sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if hi > lo {
+			i = (v - lo) * (len(sparkTicks) - 1) / (hi - lo)
+		}
+		b.WriteRune(sparkTicks[i])
+	}
+	return b.String()
+}
+
+// Bar renders v as a bar of up to width blocks relative to max.
+func Bar(v, max, width int) string {
+	if max <= 0 || v <= 0 || width <= 0 {
+		return ""
+	}
+	n := v * width / max
+	if n == 0 {
+		n = 1
+	}
+	return strings.Repeat("█", n)
+}
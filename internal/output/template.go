@@ -0,0 +1,200 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+)
+
+// TemplateFuncs are the helper functions available to --template in addition
+// to the text/template builtins.
+var TemplateFuncs = template.FuncMap{
+	// date formats a time (or RFC 3339 string) with a Go layout: {{date "2006-01-02" .CreatedAt}}
+	"date": func(layout string, v interface{}) (string, error) {
+		t, err := toTime(v)
+		if err != nil || t.IsZero() {
+			return "", err
+		}
+		return t.Format(layout), nil
+	},
+	// ago renders the time elapsed since a time, like "3d" or "5h": {{ago .UpdatedAt}}
+	"ago": func(v interface{}) (string, error) {
+		t, err := toTime(v)
+		if err != nil || t.IsZero() {
+			return "", err
+		}
+		return formatDays(time.Since(t).Hours() / 24), nil
+	},
+	// duration renders a number of days, like "2d 4h": {{duration .metrics.AvgTimeToClose}}
+	"duration": func(v interface{}) (string, error) {
+		d, err := toFloat(v)
+		if err != nil {
+			return "", err
+		}
+		return formatDays(d), nil
+	},
+	// truncate shortens s to n characters with an ellipsis: {{truncate 40 .Title}}
+	"truncate": func(n int, s string) string {
+		r := []rune(s)
+		if n <= 0 {
+			return ""
+		}
+		if len(r) <= n {
+			return s
+		}
+		if n == 1 {
+			return string(r[:1])
+		}
+		return string(r[:n-1]) + "…"
+	},
+	// join joins a list with sep: {{join ", " .Labels}}
+	"join": func(sep string, v interface{}) string {
+		rv := reflect.ValueOf(v)
+		if v == nil || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+			return fmt.Sprint(v)
+		}
+		parts := make([]string, rv.Len())
+		for i := range parts {
+			parts[i] = fmt.Sprint(rv.Index(i).Interface())
+		}
+		return strings.Join(parts, sep)
+	},
+	// int converts a number for comparisons: {{if gt (int .metrics.Open) 100}}
+	"int": toInt,
+	// float converts a number for printf: {{printf "%.1f" (float .metrics.AvgTimeToClose)}}
+	"float": toFloat,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	// json encodes a value as compact JSON: {{json .Labels}}
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+func toTime(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case *time.Time:
+		if t == nil {
+			return time.Time{}, nil
+		}
+		return *t, nil
+	case string:
+		if t == "" {
+			return time.Time{}, nil
+		}
+		return time.Parse(time.RFC3339, t)
+	case nil:
+		return time.Time{}, nil
+	}
+	return time.Time{}, fmt.Errorf("not a time: %v", v)
+}
+
+func toFloat(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case json.Number:
+		return n.Float64()
+	case float64:
+		return n, nil
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case nil:
+		return 0, nil
+	}
+	return 0, fmt.Errorf("not a number: %v", v)
+}
+
+// toInt converts a number to int; fractions are truncated.
+func toInt(v interface{}) (int, error) {
+	switch n := v.(type) {
+	case json.Number:
+		if i, err := n.Int64(); err == nil {
+			return int(i), nil
+		}
+		f, err := n.Float64()
+		return int(f), err
+	case int:
+		return n, nil
+	case int64:
+		return int(n), nil
+	case float64:
+		return int(n), nil
+	case nil:
+		return 0, nil
+	}
+	return 0, fmt.Errorf("not a number: %v", v)
+}
+
+// formatDays renders days as "2d 4h", "5h" or "12m".
+func formatDays(days float64) string {
+	if days < 0 {
+		days = 0
+	}
+	mins := int(math.Round(days * 24 * 60))
+	d, h, m := mins/(24*60), mins/60%24, mins%60
+	switch {
+	case d > 0 && h > 0:
+		return fmt.Sprintf("%dd %dh", d, h)
+	case d > 0:
+		return fmt.Sprintf("%dd", d)
+	case h > 0:
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dm", m)
+}
+
+// ParseTemplate parses a --template value: the contents of the file it names
+// when that file exists, otherwise the value itself. A file that exists but
+// cannot be read is an error rather than template text.
+func ParseTemplate(value string) (*template.Template, error) {
+	text := value
+	b, err := os.ReadFile(value)
+	switch {
+	case err == nil:
+		text = string(b)
+	case errors.Is(err, os.ErrNotExist), errors.Is(err, syscall.ENAMETOOLONG):
+		// an inline template; long ones are not valid file names
+	default:
+		return nil, fmt.Errorf("read --template: %w", err)
+	}
+	t, err := template.New("output").Funcs(TemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse --template: %w", err)
+	}
+	return t, nil
+}
+
+// WriteTemplate executes t against v as the JSON output shows it: v goes
+// through a JSON round trip, so templates use the JSON keys and times are
+// RFC 3339 strings. Numbers are json.Number values, which print as in the
+// JSON output; the int and float helpers convert them for comparisons and
+// printf.
+func WriteTemplate(w io.Writer, t *template.Template, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var data interface{}
+	if err := dec.Decode(&data); err != nil {
+		return err
+	}
+	if err := t.Execute(w, data); err != nil {
+		return fmt.Errorf("execute --template: %w", err)
+	}
+	return nil
+}
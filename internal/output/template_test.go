@@ -0,0 +1,181 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplateFuncs_Truncate(t *testing.T) {
+	truncate := TemplateFuncs["truncate"].(func(int, string) string)
+	tests := []struct {
+		n        int
+		in, want string
+	}{
+		{5, "short", "short"},
+		{4, "longer", "lon…"},
+		{1, "longer", "l"},
+		{0, "longer", ""},
+		{3, "héllo", "hé…"},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.n, tt.in); got != tt.want {
+			t.Fatalf("truncate(%d, %q) = %q, want %q", tt.n, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTemplateFuncs_Duration(t *testing.T) {
+	duration := TemplateFuncs["duration"].(func(interface{}) (string, error))
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{2.5, "2d 12h"},
+		{3, "3d"},
+		{int64(1), "1d"},
+		{json.Number("2.5"), "2d 12h"},
+		{1.0 / 24, "1h"},
+		{0.25 / 24, "15m"},
+		{-1.0, "0m"},
+		{nil, "0m"},
+	}
+	for _, tt := range tests {
+		got, err := duration(tt.in)
+		if err != nil || got != tt.want {
+			t.Fatalf("duration(%v) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := duration("2d"); err == nil {
+		t.Fatalf("expected an error for a string")
+	}
+}
+
+func TestTemplateFuncs_Date(t *testing.T) {
+	date := TemplateFuncs["date"].(func(string, interface{}) (string, error))
+	at := time.Date(2024, 3, 5, 14, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{at, "2024-03-05"},
+		{&at, "2024-03-05"},
+		{"2024-03-05T14:00:00Z", "2024-03-05"},
+		{"", ""},
+		{nil, ""},
+		{(*time.Time)(nil), ""},
+	}
+	for _, tt := range tests {
+		got, err := date("2006-01-02", tt.in)
+		if err != nil || got != tt.want {
+			t.Fatalf("date(%v) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := date("2006-01-02", "yesterday"); err == nil {
+		t.Fatalf("expected an error for a string that is not RFC 3339")
+	}
+	if _, err := date("2006-01-02", 3); err == nil {
+		t.Fatalf("expected an error for a number")
+	}
+}
+
+func TestTemplateFuncs_Join(t *testing.T) {
+	join := TemplateFuncs["join"].(func(string, interface{}) string)
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{[]string{"bug", "ui"}, "bug, ui"},
+		{[]interface{}{"a", 2}, "a, 2"},
+		{[2]int{1, 2}, "1, 2"},
+		{[]string{}, ""},
+		{"single", "single"},
+		{nil, "<nil>"},
+	}
+	for _, tt := range tests {
+		if got := join(", ", tt.in); got != tt.want {
+			t.Fatalf("join(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWriteTemplate_Numbers(t *testing.T) {
+	type metrics struct {
+		Open    int
+		Average float64 `json:"average"`
+	}
+	doc := map[string]interface{}{
+		"metrics": metrics{Open: 1500, Average: 3},
+		"counts":  []int64{2, 3},
+		"ids":     []int64{1 << 40},
+	}
+	// numbers print as in the JSON output; int and float convert them
+	tmpl, err := ParseTemplate(`{{.metrics.Open}} {{if gt (int .metrics.Open) 100}}big{{end}} {{printf "%.1f" (float .metrics.average)}} {{index .counts 1}} {{index .ids 0}} {{json .counts}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteTemplate(&buf, tmpl, doc); err != nil {
+		t.Fatalf("WriteTemplate: %v", err)
+	}
+	if got, want := buf.String(), "1500 big 3.0 3 1099511627776 [2,3]"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestTemplateFuncs_IntAndFloat(t *testing.T) {
+	toInt := TemplateFuncs["int"].(func(interface{}) (int, error))
+	toFloat := TemplateFuncs["float"].(func(interface{}) (float64, error))
+	if n, err := toInt(json.Number("42")); err != nil || n != 42 {
+		t.Fatalf("int(42) = %d, %v", n, err)
+	}
+	if n, err := toInt(json.Number("2.7")); err != nil || n != 2 {
+		t.Fatalf("int(2.7) = %d, %v", n, err)
+	}
+	if f, err := toFloat(json.Number("2.5")); err != nil || f != 2.5 {
+		t.Fatalf("float(2.5) = %v, %v", f, err)
+	}
+	if _, err := toInt("x"); err == nil {
+		t.Fatalf("expected an error for a string")
+	}
+	if _, err := toFloat(json.Number("x")); err == nil {
+		t.Fatalf("expected an error for an invalid number")
+	}
+}
+
+func TestParseTemplate_FileOrInline(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "t.tmpl")
+	if err := os.WriteFile(file, []byte("from file"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	render := func(value string) string {
+		t.Helper()
+		tmpl, err := ParseTemplate(value)
+		if err != nil {
+			t.Fatalf("ParseTemplate(%q): %v", value, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, nil); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	if got := render(file); got != "from file" {
+		t.Fatalf("expected the file contents, got %q", got)
+	}
+	if got := render("inline {{`text`}}"); got != "inline text" {
+		t.Fatalf("expected the inline template, got %q", got)
+	}
+	if long := strings.Repeat("x", 300); render(long) != long {
+		t.Fatalf("expected a long inline template")
+	}
+	// a directory exists but is not a template file
+	if _, err := ParseTemplate(dir); err == nil || !strings.Contains(err.Error(), "read --template") {
+		t.Fatalf("expected a read error, got %v", err)
+	}
+}
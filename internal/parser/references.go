@@ -3,11 +3,12 @@ package parser
 import (
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 var (
-	// full URL: https://github.com/owner/repo/issues/123
-	reFullURL = regexp.MustCompile(`https?://[^/]+/([^/]+)/([^/]+)/issues/(\d+)`)
+	// full URL: https://github.com/owner/repo/issues/123 (or a GitHub Enterprise Server host)
+	reFullURL = regexp.MustCompile(`https?://([^/\s]+)/([^/]+)/([^/]+)/issues/(\d+)`)
 	// owner/repo#123
 	reOwnerRef = regexp.MustCompile(`([A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+)#(\d+)`)
 	// short reference: #123
@@ -19,6 +20,9 @@ type Reference struct {
 	OwnerRepo string // empty when short ref
 	Number    int
 	Raw       string
+	// Host is the lowercased host of a full URL reference; empty for
+	// owner/repo#N and #N, which refer to the host of the text they appear in.
+	Host string
 }
 
 // ParseReferences finds references in a text body. Returns unique references in order found.
@@ -28,14 +32,15 @@ func ParseReferences(s string) []Reference {
 
 	// full URLs
 	for _, m := range reFullURL.FindAllStringSubmatch(s, -1) {
-		if len(m) >= 4 {
-			owner := m[1]
-			repo := m[2]
-			if n, err := strconv.Atoi(m[3]); err == nil {
-				key := owner + "/" + repo + "#" + m[3]
+		if len(m) >= 5 {
+			host := strings.ToLower(m[1])
+			owner := m[2]
+			repo := m[3]
+			if n, err := strconv.Atoi(m[4]); err == nil {
+				key := host + "/" + owner + "/" + repo + "#" + m[4]
 				if _, ok := seen[key]; !ok {
 					seen[key] = struct{}{}
-					out = append(out, Reference{OwnerRepo: owner + "/" + repo, Number: n, Raw: m[0]})
+					out = append(out, Reference{OwnerRepo: owner + "/" + repo, Number: n, Raw: m[0], Host: host})
 				}
 			}
 		}
@@ -0,0 +1,19 @@
+package parser
+
+import "testing"
+
+func TestParseReferences_KeepsURLHost(t *testing.T) {
+	refs := ParseReferences("see https://GHE.example.com/team/tool/issues/4, https://github.com/team/tool/issues/4 and #9")
+	if len(refs) != 3 {
+		t.Fatalf("expected 3 references, got %+v", refs)
+	}
+	if refs[0].Host != "ghe.example.com" || refs[0].OwnerRepo != "team/tool" || refs[0].Number != 4 {
+		t.Fatalf("unexpected enterprise reference: %+v", refs[0])
+	}
+	if refs[1].Host != "github.com" {
+		t.Fatalf("same path on another host must stay separate: %+v", refs[1])
+	}
+	if refs[2].Host != "" || refs[2].OwnerRepo != "" || refs[2].Number != 9 {
+		t.Fatalf("unexpected short reference: %+v", refs[2])
+	}
+}
@@ -0,0 +1,328 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ghapi "github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Client implements api.RESTClient on top of a Store. It answers the REST
+// endpoints the api package uses (issue list with its query parameters, single
+// issue, comments, timeline, labels, and the organization and user repository
+// lists, which contain the synced repositories) from synced data and never
+// touches the network. It does not implement api.GraphQLClient, so listing falls back to REST.
+type Client struct {
+	st   *Store
+	host string
+
+	mu    sync.Mutex
+	repos map[string]map[int]json.RawMessage
+}
+
+// NewClient returns an offline client reading the repositories of host from st.
+func NewClient(st *Store, host string) *Client {
+	return &Client{st: st, host: host, repos: map[string]map[int]json.RawMessage{}}
+}
+
+func (c *Client) Get(path string, out interface{}) error {
+	return c.GetContext(context.Background(), path, out)
+}
+
+func (c *Client) GetContext(ctx context.Context, path string, out interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	u, err := url.Parse(path)
+	if err != nil {
+		return err
+	}
+	seg := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(seg) == 3 && (seg[0] == "orgs" || seg[0] == "users") && seg[2] == "repos" {
+		items, err := c.ownerRepos(seg[1])
+		if err != nil {
+			return err
+		}
+		return remarshal(paginate(items, u.Query()), out)
+	}
+	if len(seg) < 4 || seg[0] != "repos" {
+		return fmt.Errorf("offline: %s is not available from the local store", u.Path)
+	}
+	repo := Key(c.host, seg[1]+"/"+seg[2])
+	q := u.Query()
+
+	var body interface{}
+	switch {
+	case len(seg) == 4 && seg[3] == "labels":
+		if _, err := c.issues(repo); err != nil {
+			return err
+		}
+		items, err := c.st.Labels(repo)
+		if err != nil {
+			return err
+		}
+		body = paginate(items, q)
+	case len(seg) == 4 && seg[3] == "issues":
+		issues, err := c.issues(repo)
+		if err != nil {
+			return err
+		}
+		items, err := listIssues(issues, q)
+		if err != nil {
+			return err
+		}
+		body = paginate(items, q)
+	case len(seg) >= 5 && seg[3] == "issues":
+		issues, err := c.issues(repo)
+		if err != nil {
+			return err
+		}
+		n, err := strconv.Atoi(seg[4])
+		raw, ok := issues[n]
+		if err != nil || !ok {
+			return notFound(path)
+		}
+		switch {
+		case len(seg) == 5:
+			body = raw
+		case len(seg) == 6 && seg[5] == "comments":
+			items, err := c.st.Comments(repo, n)
+			if err != nil {
+				return err
+			}
+			body = paginate(items, q)
+		case len(seg) == 6 && seg[5] == "timeline":
+			items, err := c.st.Timeline(repo, n)
+			if err != nil {
+				return err
+			}
+			body = paginate(items, q)
+		default:
+			return fmt.Errorf("offline: %s is not available from the local store", u.Path)
+		}
+	default:
+		return fmt.Errorf("offline: %s is not available from the local store", u.Path)
+	}
+
+	return remarshal(body, out)
+}
+
+// remarshal decodes body into out the way the online client would.
+func remarshal(body interface{}, out interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// ownerRepos lists the synced repositories of owner on the client's host as
+// REST repository objects. Archived state is not stored, so none is archived.
+func (c *Client) ownerRepos(owner string) ([]json.RawMessage, error) {
+	keys, err := c.st.Repos()
+	if err != nil {
+		return nil, err
+	}
+	var out []json.RawMessage
+	for _, k := range keys {
+		parts := strings.Split(k, "/")
+		if len(parts) < 2 {
+			continue
+		}
+		name := parts[len(parts)-1]
+		ownerRepo := parts[len(parts)-2] + "/" + name
+		if !strings.EqualFold(parts[len(parts)-2], owner) || Key(c.host, ownerRepo) != k {
+			continue
+		}
+		b, err := json.Marshal(map[string]interface{}{"name": name, "full_name": ownerRepo, "archived": false})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+// issues loads (once) the stored issues of repo.
+func (c *Client) issues(repo string) (map[int]json.RawMessage, error) {
+	key := strings.ToLower(repo)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if m, ok := c.repos[key]; ok {
+		return m, nil
+	}
+	if _, err := c.st.Meta(repo); err != nil {
+		return nil, fmt.Errorf("offline: %w; run 'issue-miner sync --repo %s' first", err, repo)
+	}
+	m, err := c.st.Issues(repo)
+	if err != nil {
+		return nil, err
+	}
+	c.repos[key] = m
+	return m, nil
+}
+
+// notFound mirrors the API's 404 so callers handle missing issues as they do online.
+func notFound(path string) error {
+	return &ghapi.HTTPError{StatusCode: http.StatusNotFound, Message: "Not Found (offline)", RequestURL: &url.URL{Path: path}}
+}
+
+// issueFields are the fields the issue list endpoint filters and sorts on.
+type issueFields struct {
+	Number    int       `json:"number"`
+	State     string    `json:"state"`
+	Comments  int       `json:"comments"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	User      *struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Assignees []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// listIssues applies the issue list endpoint's state, labels, assignee,
+// creator, since, sort and direction parameters, with the API's defaults
+// (open issues, newest created first).
+func listIssues(issues map[int]json.RawMessage, q url.Values) ([]json.RawMessage, error) {
+	state := q.Get("state")
+	if state == "" {
+		state = "open"
+	}
+	var labels []string
+	if l := q.Get("labels"); l != "" {
+		labels = strings.Split(l, ",")
+	}
+	var since time.Time
+	if s := q.Get("since"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, fmt.Errorf("offline: invalid since value %q: %w", s, err)
+		}
+		since = t
+	}
+
+	type entry struct {
+		f   issueFields
+		raw json.RawMessage
+	}
+	var list []entry
+	for _, raw := range issues {
+		var f issueFields
+		if err := json.Unmarshal(raw, &f); err != nil {
+			return nil, err
+		}
+		if state != "all" && !strings.EqualFold(f.State, state) {
+			continue
+		}
+		if !since.IsZero() && f.UpdatedAt.Before(since) {
+			continue
+		}
+		if !hasAllLabels(f, labels) || !matchesAssignee(f, q.Get("assignee")) {
+			continue
+		}
+		if creator := q.Get("creator"); creator != "" && (f.User == nil || !strings.EqualFold(f.User.Login, creator)) {
+			continue
+		}
+		list = append(list, entry{f: f, raw: raw})
+	}
+
+	key := func(f issueFields) int64 {
+		switch q.Get("sort") {
+		case "updated":
+			return f.UpdatedAt.UnixNano()
+		case "comments":
+			return int64(f.Comments)
+		default:
+			return f.CreatedAt.UnixNano()
+		}
+	}
+	asc := q.Get("direction") == "asc"
+	sort.SliceStable(list, func(i, j int) bool {
+		ki, kj := key(list[i].f), key(list[j].f)
+		if ki == kj {
+			// keep the order deterministic for equal keys
+			ki, kj = int64(list[i].f.Number), int64(list[j].f.Number)
+		}
+		if asc {
+			return ki < kj
+		}
+		return ki > kj
+	})
+
+	out := make([]json.RawMessage, 0, len(list))
+	for _, e := range list {
+		out = append(out, e.raw)
+	}
+	return out, nil
+}
+
+func hasAllLabels(f issueFields, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, l := range f.Labels {
+			if strings.EqualFold(l.Name, strings.TrimSpace(w)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesAssignee implements the assignee parameter: a login, "none" or "*".
+func matchesAssignee(f issueFields, assignee string) bool {
+	switch assignee {
+	case "":
+		return true
+	case "none":
+		return len(f.Assignees) == 0
+	case "*":
+		return len(f.Assignees) > 0
+	}
+	for _, a := range f.Assignees {
+		if strings.EqualFold(a.Login, assignee) {
+			return true
+		}
+	}
+	return false
+}
+
+// paginate applies per_page (default 30, at most 100) and page (default 1).
+func paginate(items []json.RawMessage, q url.Values) []json.RawMessage {
+	perPage, err := strconv.Atoi(q.Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = 30
+	}
+	if perPage > 100 {
+		perPage = 100
+	}
+	page, err := strconv.Atoi(q.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	start := (page - 1) * perPage
+	if start >= len(items) {
+		return []json.RawMessage{}
+	}
+	end := start + perPage
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end]
+}
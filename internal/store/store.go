@@ -0,0 +1,282 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Store is a local mirror of repository issues written by Sync and served by Client.
+//
+// Layout under the root directory, one directory per repository:
+//
+//	owner/repo/sync.json           sync state (Meta)
+//	owner/repo/issues.json         REST issue objects, sorted by number
+//	owner/repo/labels.json         REST label objects
+//	owner/repo/comments/<n>.json   REST comments of issue n
+//	owner/repo/timeline/<n>.json   REST timeline events of issue n
+//
+// Repositories on hosts other than github.com live under a directory named
+// after the host (host/owner/repo/...); see Key.
+//
+// Objects are stored exactly as the REST API returned them so the api package
+// decodes offline data with the same code it uses online.
+type Store struct {
+	dir string
+}
+
+// Meta records the state of the last successful sync of a repository.
+type Meta struct {
+	Repo     string    `json:"repo"`
+	SyncedAt time.Time `json:"synced_at"`
+	// LastUpdated is the newest updated_at seen; the next sync asks for changes since then.
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+// ErrNotSynced is returned when a repository has no data in the store.
+var ErrNotSynced = errors.New("repository has not been synced")
+
+// Open returns a Store rooted at dir, creating the directory if needed.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create store directory: %w", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Dir returns the root directory of the store.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// DefaultDir returns the store location used when --store is not set:
+// $XDG_DATA_HOME/gh-issue-miner, or ~/.local/share/gh-issue-miner.
+func DefaultDir() (string, error) {
+	if d := os.Getenv("XDG_DATA_HOME"); d != "" {
+		return filepath.Join(d, "gh-issue-miner"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "gh-issue-miner"), nil
+}
+
+// Key returns the name under which repo (owner/repo) on host is stored:
+// owner/repo for github.com, host/owner/repo for any other host. Unlike
+// api.QualifyRepo it does not depend on the default host, so data synced from
+// an Enterprise Server stays separate whatever GH_HOST is set to later.
+func Key(host, repo string) string {
+	host = strings.ToLower(host)
+	if host == "" || host == "github.com" {
+		return repo
+	}
+	return host + "/" + repo
+}
+
+func (s *Store) repoDir(repo string) (string, error) {
+	parts := strings.Split(repo, "/")
+	if len(parts) != 2 && len(parts) != 3 {
+		return "", fmt.Errorf("invalid repository %q: expected [host/]owner/repo", repo)
+	}
+	for _, p := range parts {
+		if p == "" || p == "." || p == ".." {
+			return "", fmt.Errorf("invalid repository %q: expected [host/]owner/repo", repo)
+		}
+	}
+	// host, owner and repo names are case-insensitive on GitHub
+	elems := []string{s.dir}
+	for _, p := range parts {
+		elems = append(elems, strings.ToLower(p))
+	}
+	return filepath.Join(elems...), nil
+}
+
+// Repos returns the keys (see Key) of all synced repositories, sorted.
+func (s *Store) Repos() ([]string, error) {
+	var out []string
+	err := filepath.WalkDir(s.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && (d.Name() == "comments" || d.Name() == "timeline") {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && d.Name() == "sync.json" {
+			var m Meta
+			if err := readJSON(p, &m); err == nil && m.Repo != "" {
+				out = append(out, m.Repo)
+			}
+		}
+		return nil
+	})
+	sort.Strings(out)
+	return out, err
+}
+
+// Meta returns the sync state of repo, or ErrNotSynced.
+func (s *Store) Meta(repo string) (Meta, error) {
+	var m Meta
+	dir, err := s.repoDir(repo)
+	if err != nil {
+		return m, err
+	}
+	if err := readJSON(filepath.Join(dir, "sync.json"), &m); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return m, fmt.Errorf("%s: %w", repo, ErrNotSynced)
+		}
+		return m, err
+	}
+	return m, nil
+}
+
+// SaveMeta records the sync state of a repository.
+func (s *Store) SaveMeta(m Meta) error {
+	dir, err := s.repoDir(m.Repo)
+	if err != nil {
+		return err
+	}
+	return writeJSON(filepath.Join(dir, "sync.json"), m)
+}
+
+// Issues returns the stored issue objects of repo keyed by number.
+func (s *Store) Issues(repo string) (map[int]json.RawMessage, error) {
+	dir, err := s.repoDir(repo)
+	if err != nil {
+		return nil, err
+	}
+	var items []json.RawMessage
+	if err := readJSON(filepath.Join(dir, "issues.json"), &items); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	out := make(map[int]json.RawMessage, len(items))
+	for _, raw := range items {
+		var head struct {
+			Number int `json:"number"`
+		}
+		if err := json.Unmarshal(raw, &head); err != nil {
+			return nil, fmt.Errorf("%s: corrupt issues.json: %w", repo, err)
+		}
+		out[head.Number] = raw
+	}
+	return out, nil
+}
+
+// SaveIssues replaces the stored issue objects of repo.
+func (s *Store) SaveIssues(repo string, issues map[int]json.RawMessage) error {
+	dir, err := s.repoDir(repo)
+	if err != nil {
+		return err
+	}
+	numbers := make([]int, 0, len(issues))
+	for n := range issues {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+	items := make([]json.RawMessage, 0, len(numbers))
+	for _, n := range numbers {
+		items = append(items, issues[n])
+	}
+	return writeJSON(filepath.Join(dir, "issues.json"), items)
+}
+
+// Labels returns the stored label objects of repo.
+func (s *Store) Labels(repo string) ([]json.RawMessage, error) {
+	return s.readList(repo, "labels.json")
+}
+
+// SaveLabels replaces the stored label objects of repo.
+func (s *Store) SaveLabels(repo string, labels []json.RawMessage) error {
+	return s.writeList(repo, "labels.json", labels)
+}
+
+// Comments returns the stored comments of an issue.
+func (s *Store) Comments(repo string, number int) ([]json.RawMessage, error) {
+	return s.readList(repo, filepath.Join("comments", strconv.Itoa(number)+".json"))
+}
+
+// SaveComments replaces the stored comments of an issue.
+func (s *Store) SaveComments(repo string, number int, comments []json.RawMessage) error {
+	return s.writeList(repo, filepath.Join("comments", strconv.Itoa(number)+".json"), comments)
+}
+
+// Timeline returns the stored timeline events of an issue.
+func (s *Store) Timeline(repo string, number int) ([]json.RawMessage, error) {
+	return s.readList(repo, filepath.Join("timeline", strconv.Itoa(number)+".json"))
+}
+
+// SaveTimeline replaces the stored timeline events of an issue.
+func (s *Store) SaveTimeline(repo string, number int, events []json.RawMessage) error {
+	return s.writeList(repo, filepath.Join("timeline", strconv.Itoa(number)+".json"), events)
+}
+
+// readList reads a JSON array; a missing file is an empty list.
+func (s *Store) readList(repo, name string) ([]json.RawMessage, error) {
+	dir, err := s.repoDir(repo)
+	if err != nil {
+		return nil, err
+	}
+	var items []json.RawMessage
+	if err := readJSON(filepath.Join(dir, name), &items); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (s *Store) writeList(repo, name string, items []json.RawMessage) error {
+	dir, err := s.repoDir(repo)
+	if err != nil {
+		return err
+	}
+	if items == nil {
+		items = []json.RawMessage{}
+	}
+	return writeJSON(filepath.Join(dir, name), items)
+}
+
+func readJSON(path string, v interface{}) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// writeJSON writes v atomically so an interrupted sync never leaves a truncated file.
+func writeJSON(path string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, werr := tmp.Write(b)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		os.Remove(tmp.Name())
+		if werr != nil {
+			return werr
+		}
+		return cerr
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
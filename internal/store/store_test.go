@@ -0,0 +1,150 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	ghapi "github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+// fakeAPI serves canned JSON by path (without query) and records the requests.
+type fakeAPI struct {
+	mu        sync.Mutex
+	responses map[string]string
+	requests  []string
+}
+
+func (f *fakeAPI) Get(path string, out interface{}) error {
+	return f.GetContext(context.Background(), path, out)
+}
+
+func (f *fakeAPI) GetContext(ctx context.Context, path string, out interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, path)
+	u, _ := url.Parse(path)
+	body, ok := f.responses[u.Path]
+	if !ok || u.Query().Get("page") != "1" {
+		body = "[]"
+	}
+	return json.Unmarshal([]byte(body), out)
+}
+
+const syncIssues = `[
+ {"number":1,"state":"open","title":"first","body":"see #2","comments":1,"user":{"login":"alice"},
+  "labels":[{"name":"bug"}],"assignees":[{"login":"bob"}],"assignee":{"login":"bob"},
+  "created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-03T00:00:00Z"},
+ {"number":2,"state":"closed","title":"second","body":"","comments":0,"user":{"login":"carol"},
+  "labels":[],"assignees":[],
+  "created_at":"2025-01-02T00:00:00Z","updated_at":"2025-01-04T00:00:00Z","closed_at":"2025-01-04T00:00:00Z"}
+]`
+
+func TestSyncThenServeOffline(t *testing.T) {
+	ctx := context.Background()
+	st, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	online := &fakeAPI{responses: map[string]string{
+		"repos/o/r/issues":            syncIssues,
+		"repos/o/r/issues/1/comments": `[{"id":11,"body":"ref #2","user":{"login":"dave"},"created_at":"2025-01-03T00:00:00Z"}]`,
+		"repos/o/r/issues/2/timeline": `[{"id":21,"event":"cross-referenced","actor":{"login":"alice"},"created_at":"2025-01-03T00:00:00Z","source":{"issue":{"number":1,"url":"https://api.github.com/repos/o/r/issues/1"}}}]`,
+		"repos/o/r/labels":            `[{"name":"bug"},{"name":"docs"}]`,
+	}}
+
+	res, err := Sync(ctx, online, st, "", "o/r", false)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if res.Updated != 2 || res.Total != 2 || res.Labels != 2 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	// the second sync only asks for changes since the newest updated_at
+	online.requests = nil
+	online.responses["repos/o/r/issues"] = `[]`
+	if res, err = Sync(ctx, online, st, "", "o/r", false); err != nil {
+		t.Fatalf("second Sync: %v", err)
+	}
+	if res.Updated != 0 || res.Total != 2 {
+		t.Fatalf("unexpected incremental result: %+v", res)
+	}
+	if !strings.Contains(online.requests[0], "since=2025-01-04T00%3A00%3A00Z") {
+		t.Fatalf("expected since parameter, got %s", online.requests[0])
+	}
+
+	off := NewClient(st, "github.com")
+	open, err := api.ListIssues(ctx, off, "o/r", 10, "open", []string{"bug"}, false, "bob", "alice", "", "", nil)
+	if err != nil {
+		t.Fatalf("ListIssues: %v", err)
+	}
+	if len(open) != 1 || open[0].Number != 1 || open[0].Assignee != "bob" {
+		t.Fatalf("unexpected filtered issues: %+v", open)
+	}
+	all, err := api.ListIssues(ctx, off, "o/r", 10, "all", nil, false, "", "", "created", "asc", nil)
+	if err != nil || len(all) != 2 || all[0].Number != 1 {
+		t.Fatalf("unexpected sorted issues: %+v (%v)", all, err)
+	}
+
+	iss, err := api.GetIssue(ctx, off, "o/r", 2)
+	if err != nil || iss.ClosedAt == nil {
+		t.Fatalf("GetIssue: %+v (%v)", iss, err)
+	}
+	comments, err := api.ListIssueComments(ctx, off, "o/r", 1)
+	if err != nil || len(comments) != 1 || comments[0].Author != "dave" {
+		t.Fatalf("ListIssueComments: %+v (%v)", comments, err)
+	}
+	events, err := api.GetIssueTimeline(ctx, off, "o/r", 2)
+	if err != nil || len(events) != 1 || events[0].SourceIssueNumber != 1 {
+		t.Fatalf("GetIssueTimeline: %+v (%v)", events, err)
+	}
+	labels, err := api.ListRepoLabels(ctx, off, "o/r")
+	if err != nil || len(labels) != 2 {
+		t.Fatalf("ListRepoLabels: %v (%v)", labels, err)
+	}
+
+	var httpErr *ghapi.HTTPError
+	if _, err := api.GetIssue(ctx, off, "o/r", 99); !errors.As(err, &httpErr) || httpErr.StatusCode != 404 {
+		t.Fatalf("expected 404 for a missing issue, got %v", err)
+	}
+	if _, err := api.ListIssues(ctx, off, "other/repo", 10, "", nil, false, "", "", "", "", nil); !errors.Is(err, ErrNotSynced) {
+		t.Fatalf("expected ErrNotSynced, got %v", err)
+	}
+}
+
+func TestSync_KeepsHostsApart(t *testing.T) {
+	ctx := context.Background()
+	st, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	ghes := &fakeAPI{responses: map[string]string{"repos/o/r/issues": syncIssues}}
+	if _, err := Sync(ctx, ghes, st, "GHE.example.com", "o/r", false); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if m, err := st.Meta("ghe.example.com/o/r"); err != nil || m.Repo != "ghe.example.com/o/r" {
+		t.Fatalf("expected meta under the host key, got %+v (%v)", m, err)
+	}
+
+	if _, err := api.GetIssue(ctx, NewClient(st, "ghe.example.com"), "o/r", 1); err != nil {
+		t.Fatalf("GetIssue on the synced host: %v", err)
+	}
+	if _, err := api.GetIssue(ctx, NewClient(st, "github.com"), "o/r", 1); !errors.Is(err, ErrNotSynced) {
+		t.Fatalf("github.com o/r must not see the enterprise data, got %v", err)
+	}
+
+	// --org works offline over the synced repositories of the host
+	if repos, err := api.ListOwnerRepos(ctx, NewClient(st, "ghe.example.com"), "o"); err != nil || len(repos) != 1 || repos[0].FullName != "o/r" {
+		t.Fatalf("ListOwnerRepos on the synced host: %+v (%v)", repos, err)
+	}
+	if repos, err := api.ListOwnerRepos(ctx, NewClient(st, "github.com"), "o"); err != nil || len(repos) != 0 {
+		t.Fatalf("ListOwnerRepos on github.com: %+v (%v)", repos, err)
+	}
+}
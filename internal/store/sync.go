@@ -0,0 +1,178 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+// syncWorkers bounds concurrent comment/timeline downloads, matching the graph worker pool.
+const syncWorkers = 5
+
+// SyncResult summarizes a Sync run.
+type SyncResult struct {
+	Updated int // issues (and pull requests) created or changed since the last sync
+	Total   int // issues now in the store
+	Labels  int
+}
+
+// Sync mirrors repo (owner/repo) on host into the store under Key(host, repo). Only issues updated since the previous sync
+// are downloaded (using the list endpoint's since parameter); for each of them
+// the comments and timeline are replaced. full ignores the previous sync state.
+// The sync state is only advanced after everything has been written, so an
+// interrupted sync is resumed by the next one.
+func Sync(ctx context.Context, client api.RESTClient, st *Store, host, repo string, full bool) (SyncResult, error) {
+	var res SyncResult
+	key := Key(host, repo)
+	issues := map[int]json.RawMessage{}
+	var since *time.Time
+	if !full {
+		if m, err := st.Meta(key); err == nil {
+			since = &m.LastUpdated
+			if issues, err = st.Issues(key); err != nil {
+				return res, err
+			}
+		}
+	}
+	started := time.Now().UTC()
+
+	qs := url.Values{}
+	qs.Set("state", "all")
+	qs.Set("sort", "updated")
+	qs.Set("direction", "asc")
+	if since != nil && !since.IsZero() {
+		qs.Set("since", since.Format(time.RFC3339))
+	}
+	changed, err := listAll(ctx, client, fmt.Sprintf("repos/%s/issues?%s", repo, qs.Encode()))
+	if err != nil {
+		return res, err
+	}
+
+	var lastUpdated time.Time
+	if since != nil {
+		lastUpdated = *since
+	}
+	var numbers []int
+	for _, raw := range changed {
+		var head struct {
+			Number    int       `json:"number"`
+			UpdatedAt time.Time `json:"updated_at"`
+		}
+		if err := json.Unmarshal(raw, &head); err != nil {
+			return res, fmt.Errorf("decode issue from %s: %w", key, err)
+		}
+		issues[head.Number] = raw
+		numbers = append(numbers, head.Number)
+		if head.UpdatedAt.After(lastUpdated) {
+			lastUpdated = head.UpdatedAt
+		}
+	}
+
+	if err := syncDetails(ctx, client, st, repo, key, numbers); err != nil {
+		return res, err
+	}
+
+	labels, err := listAll(ctx, client, fmt.Sprintf("repos/%s/labels", repo))
+	if err != nil {
+		return res, err
+	}
+	if err := st.SaveLabels(key, labels); err != nil {
+		return res, err
+	}
+	if err := st.SaveIssues(key, issues); err != nil {
+		return res, err
+	}
+	if err := st.SaveMeta(Meta{Repo: key, SyncedAt: started, LastUpdated: lastUpdated}); err != nil {
+		return res, err
+	}
+	res.Updated = len(numbers)
+	res.Total = len(issues)
+	res.Labels = len(labels)
+	return res, nil
+}
+
+// syncDetails replaces the comments and timeline of each issue with a small
+// worker pool. repo is used for API paths, key for the store.
+func syncDetails(ctx context.Context, client api.RESTClient, st *Store, repo, key string, numbers []int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+		mu.Unlock()
+	}
+	sem := make(chan struct{}, syncWorkers)
+	for _, n := range numbers {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			comments, err := listAll(ctx, client, fmt.Sprintf("repos/%s/issues/%d/comments", repo, n))
+			if err == nil {
+				err = st.SaveComments(key, n, comments)
+			}
+			if err != nil {
+				fail(fmt.Errorf("sync comments of %s#%d: %w", key, n, err))
+				return
+			}
+			events, err := listAll(ctx, client, fmt.Sprintf("repos/%s/issues/%d/timeline", repo, n))
+			if err == nil {
+				err = st.SaveTimeline(key, n, events)
+			}
+			if err != nil {
+				fail(fmt.Errorf("sync timeline of %s#%d: %w", key, n, err))
+			}
+		}(n)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// listAll follows page-number pagination of a REST list endpoint and returns the raw items.
+func listAll(ctx context.Context, client api.RESTClient, path string) ([]json.RawMessage, error) {
+	const perPage = 100
+	sep := "?"
+	if u, err := url.Parse(path); err == nil && u.RawQuery != "" {
+		sep = "&"
+	}
+	var out []json.RawMessage
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var items []json.RawMessage
+		p := path + sep + "per_page=" + strconv.Itoa(perPage) + "&page=" + strconv.Itoa(page)
+		if err := client.GetContext(ctx, p, &items); err != nil {
+			return nil, err
+		}
+		out = append(out, items...)
+		if len(items) < perPage {
+			return out, nil
+		}
+	}
+}
@@ -0,0 +1,494 @@
+// Package testserver serves an in-memory fake of the GitHub REST endpoints
+// used by issue-miner over httptest, so commands can be tested end to end.
+//
+// Supported endpoints:
+//
+//	GET repos/{owner}/{repo}/issues                  state, labels, assignee, creator, since, sort, direction, per_page, page
+//	GET repos/{owner}/{repo}/issues/{number}
+//	GET repos/{owner}/{repo}/issues/{number}/comments per_page, page
+//	GET repos/{owner}/{repo}/issues/{number}/timeline per_page, page
+//	GET repos/{owner}/{repo}/labels                  per_page, page
+//	GET orgs/{owner}/repos, users/{owner}/repos      per_page, page
+//
+// Both repository lists serve every repository of the owner; the fake does not
+// distinguish organizations from users.
+//
+// Point the api package at it with api.Settings.BaseURL = srv.URL().
+package testserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Issue is an issue or pull request served by the fake.
+type Issue struct {
+	Number      int
+	State       string // "open" (default) or "closed"
+	Title       string
+	Body        string
+	Author      string
+	Labels      []string
+	Assignees   []string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ClosedAt    *time.Time
+	PullRequest bool
+	Comments    []Comment
+	Timeline    []Event
+
+	Milestone         string
+	StateReason       string
+	Locked            bool
+	AuthorAssociation string
+}
+
+// Comment is an issue comment.
+type Comment struct {
+	ID                int64
+	Author            string
+	Body              string
+	CreatedAt         time.Time
+	AuthorAssociation string
+}
+
+// Event is a timeline event. SourceRepo/SourceNumber describe the referencing
+// issue of a cross-referenced event.
+type Event struct {
+	ID           int64
+	Event        string
+	Actor        string
+	CreatedAt    time.Time
+	SourceRepo   string
+	SourceNumber int
+}
+
+// Server is a running fake API.
+type Server struct {
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	repos    map[string]*repo
+	requests []string
+}
+
+type repo struct {
+	fullName string
+	archived bool
+	issues   map[int]Issue
+	labels   []string
+}
+
+// New starts a fake API server. Call Close when done.
+func New() *Server {
+	s := &Server{repos: map[string]*repo{}}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// URL returns the base URL to use as api.Settings.BaseURL.
+func (s *Server) URL() string {
+	return s.srv.URL + "/"
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// AddIssues adds (or replaces) issues of ownerRepo. Labels used by the issues
+// are added to the repository labels.
+func (s *Server) AddIssues(ownerRepo string, issues ...Issue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.repo(ownerRepo)
+	for _, it := range issues {
+		if it.State == "" {
+			it.State = "open"
+		}
+		if it.UpdatedAt.IsZero() {
+			it.UpdatedAt = it.CreatedAt
+		}
+		r.issues[it.Number] = it
+		for _, l := range it.Labels {
+			r.addLabel(l)
+		}
+	}
+}
+
+// AddLabels adds repository labels that no issue uses yet.
+func (s *Server) AddLabels(ownerRepo string, names ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.repo(ownerRepo)
+	for _, n := range names {
+		r.addLabel(n)
+	}
+}
+
+// SetArchived marks ownerRepo (created if needed) as archived in the repository lists.
+func (s *Server) SetArchived(ownerRepo string, archived bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repo(ownerRepo).archived = archived
+}
+
+// Requests returns the request paths (with query strings, without the leading
+// slash) received so far.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) repo(ownerRepo string) *repo {
+	key := strings.ToLower(ownerRepo)
+	r, ok := s.repos[key]
+	if !ok {
+		r = &repo{fullName: ownerRepo, issues: map[int]Issue{}}
+		s.repos[key] = r
+	}
+	return r
+}
+
+func (r *repo) addLabel(name string) {
+	for _, l := range r.labels {
+		if strings.EqualFold(l, name) {
+			return
+		}
+	}
+	r.labels = append(r.labels, name)
+}
+
+func (s *Server) handle(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, strings.TrimPrefix(req.URL.RequestURI(), "/"))
+
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	seg := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(seg) == 3 && (seg[0] == "orgs" || seg[0] == "users") && seg[2] == "repos" {
+		writePage(w, req.URL.Query(), s.ownerRepos(seg[1]))
+		return
+	}
+	if len(seg) < 4 || seg[0] != "repos" {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	r, ok := s.repos[strings.ToLower(seg[1]+"/"+seg[2])]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	base := "http://" + req.Host + "/repos/" + seg[1] + "/" + seg[2]
+	q := req.URL.Query()
+
+	switch {
+	case len(seg) == 4 && seg[3] == "labels":
+		var out []interface{}
+		for _, l := range r.labels {
+			out = append(out, map[string]interface{}{"name": l})
+		}
+		writePage(w, q, out)
+	case len(seg) == 4 && seg[3] == "issues":
+		issues, status, msg := r.list(q)
+		if status != http.StatusOK {
+			writeError(w, status, msg)
+			return
+		}
+		var out []interface{}
+		for _, it := range issues {
+			out = append(out, issueJSON(base, it))
+		}
+		writePage(w, q, out)
+	case len(seg) >= 5 && seg[3] == "issues":
+		n, err := strconv.Atoi(seg[4])
+		it, ok := r.issues[n]
+		if err != nil || !ok {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		switch {
+		case len(seg) == 5:
+			writeJSON(w, issueJSON(base, it))
+		case len(seg) == 6 && seg[5] == "comments":
+			var out []interface{}
+			for _, c := range it.Comments {
+				out = append(out, map[string]interface{}{
+					"id":         c.ID,
+					"body":       c.Body,
+					"user":       user(c.Author),
+					"created_at": c.CreatedAt.UTC().Format(time.RFC3339),
+
+					"author_association": c.AuthorAssociation,
+				})
+			}
+			writePage(w, q, out)
+		case len(seg) == 6 && seg[5] == "timeline":
+			var out []interface{}
+			for _, e := range it.Timeline {
+				out = append(out, eventJSON(req.Host, e))
+			}
+			writePage(w, q, out)
+		default:
+			writeError(w, http.StatusNotFound, "Not Found")
+		}
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// ownerRepos returns the repository objects of owner sorted by name.
+func (s *Server) ownerRepos(owner string) []interface{} {
+	var names []string
+	for _, r := range s.repos {
+		if o, _, _ := strings.Cut(r.fullName, "/"); strings.EqualFold(o, owner) {
+			names = append(names, r.fullName)
+		}
+	}
+	sort.Strings(names)
+	var out []interface{}
+	for _, n := range names {
+		_, name, _ := strings.Cut(n, "/")
+		out = append(out, map[string]interface{}{
+			"name":      name,
+			"full_name": n,
+			"archived":  s.repos[strings.ToLower(n)].archived,
+		})
+	}
+	return out
+}
+
+// list applies the list endpoint's filters and ordering like the real API:
+// state defaults to open, labels must all be present, since compares updated_at.
+func (r *repo) list(q map[string][]string) ([]Issue, int, string) {
+	get := func(k string) string {
+		if v := q[k]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	state := get("state")
+	switch state {
+	case "":
+		state = "open"
+	case "open", "closed", "all":
+	default:
+		return nil, http.StatusUnprocessableEntity, "Validation Failed: state"
+	}
+	var since time.Time
+	if v := get("since"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, http.StatusUnprocessableEntity, "Validation Failed: since"
+		}
+		since = t
+	}
+	var labels []string
+	if v := get("labels"); v != "" {
+		labels = strings.Split(v, ",")
+	}
+	assignee, creator := get("assignee"), get("creator")
+
+	var out []Issue
+	for _, it := range r.issues {
+		if state != "all" && it.State != state {
+			continue
+		}
+		if !since.IsZero() && it.UpdatedAt.Before(since) {
+			continue
+		}
+		if creator != "" && !strings.EqualFold(it.Author, creator) {
+			continue
+		}
+		if !containsAll(it.Labels, labels) || !assigneeMatches(it.Assignees, assignee) {
+			continue
+		}
+		out = append(out, it)
+	}
+
+	field := get("sort")
+	desc := get("direction") != "asc"
+	before := func(a, b Issue) bool {
+		switch {
+		case field == "updated" && !a.UpdatedAt.Equal(b.UpdatedAt):
+			return a.UpdatedAt.Before(b.UpdatedAt)
+		case field == "comments" && len(a.Comments) != len(b.Comments):
+			return len(a.Comments) < len(b.Comments)
+		case field != "updated" && field != "comments" && !a.CreatedAt.Equal(b.CreatedAt):
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.Number < b.Number
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if desc {
+			return before(out[j], out[i])
+		}
+		return before(out[i], out[j])
+	})
+	return out, http.StatusOK, ""
+}
+
+func containsAll(have, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if strings.EqualFold(h, strings.TrimSpace(w)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func assigneeMatches(assignees []string, filter string) bool {
+	switch filter {
+	case "":
+		return true
+	case "none":
+		return len(assignees) == 0
+	case "*":
+		return len(assignees) > 0
+	}
+	for _, a := range assignees {
+		if strings.EqualFold(a, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+func user(login string) interface{} {
+	if login == "" {
+		return nil
+	}
+	// GitHub Apps act as users named like "dependabot[bot]" of type Bot
+	typ := "User"
+	if strings.HasSuffix(login, "[bot]") {
+		typ = "Bot"
+	}
+	return map[string]interface{}{"login": login, "type": typ}
+}
+
+func issueJSON(base string, it Issue) map[string]interface{} {
+	labels := []interface{}{}
+	for _, l := range it.Labels {
+		labels = append(labels, map[string]interface{}{"name": l})
+	}
+	assignees := []interface{}{}
+	for _, a := range it.Assignees {
+		assignees = append(assignees, user(a))
+	}
+	var assignee interface{}
+	if len(it.Assignees) > 0 {
+		assignee = user(it.Assignees[0])
+	}
+	m := map[string]interface{}{
+		"url":        fmt.Sprintf("%s/issues/%d", base, it.Number),
+		"number":     it.Number,
+		"state":      it.State,
+		"title":      it.Title,
+		"body":       it.Body,
+		"user":       user(it.Author),
+		"labels":     labels,
+		"assignee":   assignee,
+		"assignees":  assignees,
+		"comments":   len(it.Comments),
+		"created_at": it.CreatedAt.UTC().Format(time.RFC3339),
+		"updated_at": it.UpdatedAt.UTC().Format(time.RFC3339),
+		"closed_at":  nil,
+
+		"html_url":           strings.Replace(fmt.Sprintf("%s/issues/%d", base, it.Number), "/repos/", "/", 1),
+		"locked":             it.Locked,
+		"author_association": it.AuthorAssociation,
+		"milestone":          nil,
+		"state_reason":       nil,
+	}
+	if it.Milestone != "" {
+		m["milestone"] = map[string]interface{}{"title": it.Milestone}
+	}
+	if it.StateReason != "" {
+		m["state_reason"] = it.StateReason
+	}
+	if it.ClosedAt != nil {
+		m["closed_at"] = it.ClosedAt.UTC().Format(time.RFC3339)
+	}
+	if it.PullRequest {
+		m["pull_request"] = map[string]interface{}{"url": fmt.Sprintf("%s/pulls/%d", base, it.Number)}
+	}
+	return m
+}
+
+func eventJSON(host string, e Event) map[string]interface{} {
+	m := map[string]interface{}{
+		"id":         e.ID,
+		"event":      e.Event,
+		"actor":      user(e.Actor),
+		"created_at": e.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if e.SourceNumber != 0 {
+		m["source"] = map[string]interface{}{
+			"type": "issue",
+			"issue": map[string]interface{}{
+				"number": e.SourceNumber,
+				"url":    fmt.Sprintf("http://%s/repos/%s/issues/%d", host, e.SourceRepo, e.SourceNumber),
+			},
+		}
+	}
+	return m
+}
+
+// writePage applies per_page (default 30, at most 100) and page (default 1).
+func writePage(w http.ResponseWriter, q map[string][]string, items []interface{}) {
+	atoi := func(k string, def int) int {
+		if v := q[k]; len(v) > 0 {
+			if n, err := strconv.Atoi(v[0]); err == nil && n > 0 {
+				return n
+			}
+		}
+		return def
+	}
+	perPage := atoi("per_page", 30)
+	if perPage > 100 {
+		perPage = 100
+	}
+	page := atoi("page", 1)
+	start := (page - 1) * perPage
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + perPage
+	if end > len(items) {
+		end = len(items)
+	}
+	out := items[start:end]
+	if out == nil {
+		out = []interface{}{}
+	}
+	writeJSON(w, out)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": msg})
+}
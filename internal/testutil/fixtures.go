@@ -0,0 +1,31 @@
+// Package testutil holds helpers shared by tests across packages.
+package testutil
+
+import (
+	"testing"
+
+	"github.com/solvaholic/gh-issue-miner/internal/api"
+)
+
+// LoadFixtures returns a client replaying the fixtures recorded (with --record)
+// in dir. The test fails if the directory cannot be loaded.
+func LoadFixtures(t testing.TB, dir string) *api.ReplayClient {
+	t.Helper()
+	rc, err := api.NewReplayClient(dir)
+	if err != nil {
+		t.Fatalf("load fixtures: %v", err)
+	}
+	return rc
+}
+
+// UseFixtures points api.NewClient and api.NewHostClient at the fixtures in
+// dir until the test ends, so commands can be run end to end without network access.
+func UseFixtures(t testing.TB, dir string) *api.ReplayClient {
+	t.Helper()
+	rc := LoadFixtures(t, dir)
+	orig, origHost := api.NewClient, api.NewHostClient
+	api.NewClient = func() (api.RESTClient, error) { return rc, nil }
+	api.NewHostClient = func(string) (api.RESTClient, error) { return rc, nil }
+	t.Cleanup(func() { api.NewClient, api.NewHostClient = orig, origHost })
+	return rc
+}
@@ -8,30 +8,32 @@ import (
 	"strings"
 )
 
-var issueURLRe = regexp.MustCompile(`(?i)^https?://[^/]+/([^/]+)/([^/]+)/issues/(\d+)$`)
+var issueURLRe = regexp.MustCompile(`(?i)^https?://([^/]+)/([^/]+)/([^/]+)/issues/(\d+)$`)
 
-// ParseIssueURL attempts to parse a GitHub issue URL and returns owner/repo and issue number.
-// Returns repo as "owner/repo" and issue number (int) on success, otherwise empty/0 and false.
-func ParseIssueURL(s string) (string, int, bool) {
+// ParseIssueURL attempts to parse a GitHub issue URL and returns its host, owner/repo and issue number.
+// The host is lowercased, so github.com and GitHub Enterprise Server URLs
+// stay distinguishable. Returns empty values and false when s is not an issue URL.
+func ParseIssueURL(s string) (string, string, int, bool) {
 	s = strings.TrimSpace(s)
 	if s == "" {
-		return "", 0, false
+		return "", "", 0, false
 	}
 	// quick regex match
-	if m := issueURLRe.FindStringSubmatch(s); len(m) == 4 {
-		num, err := strconv.Atoi(m[3])
+	if m := issueURLRe.FindStringSubmatch(s); len(m) == 5 {
+		num, err := strconv.Atoi(m[4])
 		if err != nil {
-			return "", 0, false
+			return "", "", 0, false
 		}
-		repo := m[1] + "/" + m[2]
-		return repo, num, true
+		repo := m[2] + "/" + m[3]
+		return strings.ToLower(m[1]), repo, num, true
 	}
 
 	// try url parsing and tolerant patterns
 	u, err := url.Parse(s)
 	if err != nil {
-		return "", 0, false
+		return "", "", 0, false
 	}
+	host := strings.ToLower(u.Host)
 	// path should be /owner/repo/issues/number (possibly with trailing .git removed)
 	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
 	if len(parts) >= 4 && parts[len(parts)-2] == "issues" {
@@ -40,7 +42,7 @@ func ParseIssueURL(s string) (string, int, bool) {
 		// last part is number
 		numPart := parts[len(parts)-1]
 		if n, err := strconv.Atoi(numPart); err == nil {
-			return owner + "/" + strings.TrimSuffix(repo, ".git"), n, true
+			return host, owner + "/" + strings.TrimSuffix(repo, ".git"), n, true
 		}
 	}
 
@@ -52,10 +54,10 @@ func ParseIssueURL(s string) (string, int, bool) {
 			owner := segs[i-2]
 			repo := segs[i-1]
 			if n, err := strconv.Atoi(segs[i+1]); err == nil {
-				return owner + "/" + strings.TrimSuffix(repo, ".git"), n, true
+				return host, owner + "/" + strings.TrimSuffix(repo, ".git"), n, true
 			}
 		}
 	}
 
-	return "", 0, false
+	return "", "", 0, false
 }
@@ -8,52 +8,79 @@ import (
 	"strings"
 )
 
-// DetectRepo returns repository in owner/repo form. Preference order:
+// DetectRepo returns the host and repository (owner/repo) to work on. Preference order:
 // 1. provided flagRepo (non-empty)
 // 2. GH_REPO env var
 // 3. parse `git config --get remote.origin.url`
-func DetectRepo(flagRepo string) (string, error) {
+//
+// The flag and GH_REPO accept [HOST/]OWNER/REPO. The host is empty when none
+// was given, meaning the default host; remotes always report theirs.
+func DetectRepo(flagRepo string) (string, string, error) {
 	if flagRepo != "" {
-		return flagRepo, nil
+		host, repo := SplitRepo(flagRepo)
+		return host, repo, nil
 	}
 	if g := os.Getenv("GH_REPO"); g != "" {
-		return g, nil
+		host, repo := SplitRepo(g)
+		return host, repo, nil
 	}
 
 	// try git
 	out, err := exec.Command("git", "config", "--get", "remote.origin.url").Output()
 	if err != nil {
-		return "", errors.New("could not detect repository: set --repo or GH_REPO, or run inside a git repo with origin remote")
+		return "", "", errors.New("could not detect repository: set --repo or GH_REPO, or run inside a git repo with origin remote")
 	}
 	s := strings.TrimSpace(string(out))
-	repo := parseRemoteURL(s)
+	host, repo := parseRemoteURL(s)
 	if repo == "" {
-		return "", errors.New("could not parse remote URL to owner/repo; specify --repo")
+		return "", "", errors.New("could not parse remote URL to owner/repo; specify --repo")
 	}
-	return repo, nil
+	return host, repo, nil
 }
 
-var reSSH = regexp.MustCompile(`^git@[^:]+:([^/]+)/(.+?)(?:\.git)?$`)
-var reHTTPS = regexp.MustCompile(`^https?://[^/]+/([^/]+)/(.+?)(?:\.git)?$`)
+// SplitRepo splits a repository reference of the form HOST/OWNER/REPO into
+// its lowercased host and owner/repo. OWNER/REPO is returned with an empty host.
+func SplitRepo(ref string) (string, string) {
+	if strings.Contains(ref, "://") {
+		if host, repo := parseRemoteURL(ref); repo != "" {
+			return host, repo
+		}
+	}
+	if parts := strings.Split(ref, "/"); len(parts) == 3 {
+		return strings.ToLower(parts[0]), parts[1] + "/" + parts[2]
+	}
+	return "", ref
+}
+
+var reSSH = regexp.MustCompile(`^[^@/]+@([^:]+):([^/]+)/(.+?)(?:\.git)?$`)
+var reHTTPS = regexp.MustCompile(`^https?://(?:[^@/]+@)?([^/]+)/([^/]+)/(.+?)(?:\.git)?/?$`)
 
-func parseRemoteURL(s string) string {
+// parseRemoteURL returns the lowercased host and owner/repo of a git remote URL.
+func parseRemoteURL(s string) (string, string) {
 	s = strings.TrimSpace(s)
 	if s == "" {
-		return ""
+		return "", ""
 	}
-	if m := reSSH.FindStringSubmatch(s); len(m) == 3 {
-		return m[1] + "/" + strings.TrimSuffix(m[2], ".git")
+	if m := reSSH.FindStringSubmatch(s); len(m) == 4 {
+		return strings.ToLower(m[1]), m[2] + "/" + strings.TrimSuffix(m[3], ".git")
 	}
-	if m := reHTTPS.FindStringSubmatch(s); len(m) == 3 {
-		return m[1] + "/" + strings.TrimSuffix(m[2], ".git")
+	if m := reHTTPS.FindStringSubmatch(s); len(m) == 4 {
+		return strings.ToLower(m[1]), m[2] + "/" + strings.TrimSuffix(m[3], ".git")
 	}
-	// support ssh://git@github.com/owner/repo.git
+	// support ssh://git@github.com/owner/repo.git (optionally with a port)
 	if strings.HasPrefix(s, "ssh://") {
 		// remove protocol
 		parts := strings.SplitN(strings.TrimPrefix(s, "ssh://"), "/", 3)
 		if len(parts) >= 3 {
-			return parts[1] + "/" + strings.TrimSuffix(parts[2], ".git")
+			host := parts[0]
+			if i := strings.LastIndex(host, "@"); i >= 0 {
+				host = host[i+1:]
+			}
+			if i := strings.Index(host, ":"); i >= 0 {
+				host = host[:i]
+			}
+			return strings.ToLower(host), parts[1] + "/" + strings.TrimSuffix(parts[2], ".git")
 		}
 	}
-	return ""
+	return "", ""
 }
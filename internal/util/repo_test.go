@@ -0,0 +1,43 @@
+package util
+
+import "testing"
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantHost string
+		wantRepo string
+	}{
+		{"git@github.com:cli/cli.git", "github.com", "cli/cli"},
+		{"git@ghe.example.com:team/tool.git", "ghe.example.com", "team/tool"},
+		{"https://github.com/owner/repo.git", "github.com", "owner/repo"},
+		{"https://GHE.example.com/team/tool", "ghe.example.com", "team/tool"},
+		{"https://user@ghe.example.com/team/tool.git", "ghe.example.com", "team/tool"},
+		{"ssh://git@ghe.example.com:2222/team/tool.git", "ghe.example.com", "team/tool"},
+		{"not a remote", "", ""},
+	}
+	for _, tt := range tests {
+		h, r := parseRemoteURL(tt.in)
+		if h != tt.wantHost || r != tt.wantRepo {
+			t.Fatalf("parseRemoteURL(%q) = (%q,%q), want (%q,%q)", tt.in, h, r, tt.wantHost, tt.wantRepo)
+		}
+	}
+}
+
+func TestSplitRepo(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantHost string
+		wantRepo string
+	}{
+		{"owner/repo", "", "owner/repo"},
+		{"GHE.example.com/team/tool", "ghe.example.com", "team/tool"},
+		{"https://ghe.example.com/team/tool", "ghe.example.com", "team/tool"},
+	}
+	for _, tt := range tests {
+		h, r := SplitRepo(tt.in)
+		if h != tt.wantHost || r != tt.wantRepo {
+			t.Fatalf("SplitRepo(%q) = (%q,%q), want (%q,%q)", tt.in, h, r, tt.wantHost, tt.wantRepo)
+		}
+	}
+}